@@ -12,3 +12,6 @@ type ContainerRequest = testcontainers.ContainerRequest
 
 // ContainerFile is an alias of testcontainers.ContainerFile to avoid extra import.
 type ContainerFile = testcontainers.ContainerFile
+
+// TerminateOption is an alias of testcontainers.TerminateOption to avoid extra import.
+type TerminateOption = testcontainers.TerminateOption
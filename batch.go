@@ -0,0 +1,240 @@
+package testcontainers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// batch starts multiple generic containers and collects the results.
+type batch struct {
+	opts     batchOptions
+	requests []StartGenericContainerRequest
+
+	ctx    context.Context // nolint: containedctx
+	cancel context.CancelCauseFunc
+	sem    chan struct{}
+
+	mu       sync.Mutex
+	options  []genericContainerOptions
+	results  StartedContainers
+	errs     errorCollection
+	failed   map[string]struct{}
+	cause    error
+	canceled []error
+}
+
+func newBatch(ctx context.Context, requests []StartGenericContainerRequest, o batchOptions) *batch {
+	b := &batch{
+		opts:     o,
+		requests: requests,
+		ctx:      ctx,
+		cancel:   func(error) {},
+		options:  make([]genericContainerOptions, len(requests)),
+		results:  make(StartedContainers, len(requests)),
+		errs:     make(errorCollection, 0, len(requests)),
+		failed:   make(map[string]struct{}),
+	}
+
+	origin := captureOrigin(o.test)
+
+	for i, r := range requests {
+		b.options[i] = newGenericContainerOptions(r.Request, r.Options)
+		b.options[i].origin = origin
+		b.results[i] = StartedContainer{
+			Name:          r.Request.Name,
+			ContainerName: b.options[i].request.Name,
+		}
+	}
+
+	if o.failFast {
+		b.ctx, b.cancel = context.WithCancelCause(ctx)
+	}
+
+	if o.maxConcurrency > 0 {
+		b.sem = make(chan struct{}, o.maxConcurrency)
+	}
+
+	return b
+}
+
+// start starts the requests level by level.
+func (b *batch) start(levels [][]int) {
+	for l, level := range levels {
+		for _, i := range level {
+			b.results[i].level = l
+		}
+
+		if b.cause != nil {
+			for _, i := range level {
+				err := &ContainerError{Name: b.requests[i].Request.Name, Phase: PhaseStart, Err: b.ctx.Err()}
+
+				b.results[i].Err = err
+				b.canceled = append(b.canceled, err)
+			}
+
+			continue
+		}
+
+		b.startLevel(level)
+	}
+}
+
+// startLevel starts the requests in the same level in parallel, the ones with higher priority are started first.
+func (b *batch) startLevel(level []int) {
+	var wg sync.WaitGroup
+
+	for _, i := range b.prioritize(level) {
+		if !b.dependenciesStarted(i) {
+			continue
+		}
+
+		if err := b.acquire(); err != nil {
+			b.done(i, nil, PhaseStart, err)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer b.release()
+
+			c, phase, err := startGenericContainer(b.ctx, b.requests[i].Request.Name, b.options[i])
+
+			b.done(i, c, phase, err)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func (b *batch) prioritize(level []int) []int {
+	level = slices.Clone(level)
+
+	slices.SortStableFunc(level, func(i, j int) int {
+		return cmp.Compare(b.requests[j].Priority, b.requests[i].Priority)
+	})
+
+	return level
+}
+
+func (b *batch) dependenciesStarted(i int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	r := b.requests[i]
+
+	dep, ok := failedDependency(r, b.failed)
+	if !ok {
+		return true
+	}
+
+	err := &ContainerError{
+		Name:  r.Request.Name,
+		Phase: PhaseStart,
+		Err:   fmt.Errorf("%w: %q", ErrDependencyFailed, dep),
+	}
+
+	b.results[i].Err = err
+	b.errs.Append(err)
+	b.failed[r.Request.Name] = struct{}{}
+
+	return false
+}
+
+// acquire waits until a start is allowed to run if the concurrency is limited.
+func (b *batch) acquire() error {
+	if b.sem == nil {
+		return nil
+	}
+
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case b.sem <- struct{}{}:
+		return nil
+
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
+
+func (b *batch) release() {
+	if b.sem != nil {
+		<-b.sem
+	}
+}
+
+func (b *batch) done(i int, c Container, phase Phase, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.results[i].Container = c
+
+	if err == nil {
+		return
+	}
+
+	name := b.requests[i].Request.Name
+	cErr := &ContainerError{Name: name, Phase: phase, Err: err}
+
+	if c != nil {
+		cErr.ID = c.GetContainerID()
+	}
+
+	err = cErr
+
+	b.results[i].Err = err
+	b.failed[name] = struct{}{}
+
+	switch {
+	case !b.opts.failFast:
+		b.errs.Append(err)
+
+	case b.cause == nil:
+		b.cause = err
+		b.cancel(err)
+
+	case errors.Is(err, context.Canceled):
+		b.canceled = append(b.canceled, err)
+
+	default:
+		b.errs.Append(err)
+	}
+}
+
+// rollback terminates all the started containers in the reverse order of the dependencies and removes them from the
+// results.
+func (b *batch) rollback(ctx context.Context) {
+	errs := stopContainers(ctx, b.results.stopLevels(), stopOptions{})
+
+	for i := range b.results {
+		b.results[i].Container = nil
+	}
+
+	b.errs = append(b.errs, errs...)
+}
+
+func (b *batch) hasErrors() bool {
+	return b.cause != nil || len(b.errs) > 0
+}
+
+func (b *batch) err() error {
+	if b.cause == nil {
+		return b.errs.AsError()
+	}
+
+	errs := make(errorCollection, 0, len(b.errs)+1)
+
+	errs.Append(&FailFastError{Cause: b.cause, Canceled: b.canceled})
+	errs = append(errs, b.errs...)
+
+	return errs.AsError()
+}
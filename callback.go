@@ -0,0 +1,156 @@
+package testcontainers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type containerCallback struct {
+	name string
+	f    ContainerCallback
+}
+
+type callbackErrorOptions struct {
+	terminate   bool
+	captureLogs bool
+	logsTail    int
+}
+
+// CallbackError is returned when a callback fails and WithTerminateOnCallbackError or WithLogsOnCallbackError is used.
+type CallbackError struct {
+	// Index is the index of the callback, starting from 0.
+	Index int
+	// Name is the name of the callback, it is empty if the callback is not added by WithNamedCallback.
+	Name string
+	// Logs contains the logs of the container before it is terminated, if required.
+	Logs string
+	// Err is the cause.
+	Err error
+}
+
+// Error satisfies error interface.
+func (e *CallbackError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("callback #%d", e.Index))
+
+	if e.Name != "" {
+		sb.WriteString(fmt.Sprintf(" %q", e.Name))
+	}
+
+	sb.WriteString(" failed: ")
+	sb.WriteString(e.Err.Error())
+
+	if e.Logs != "" {
+		sb.WriteString("\nlogs:\n")
+		sb.WriteString(e.Logs)
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the cause.
+func (e *CallbackError) Unwrap() error {
+	return e.Err
+}
+
+// WithNamedCallback adds a new callback with a name to run after the container is ready. The name is used in the
+// CallbackError.
+func WithNamedCallback(name string, f ContainerCallback) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.callbacks = append(o.callbacks, containerCallback{name: name, f: f})
+	})
+}
+
+// WithTerminateOnCallbackError terminates the container if any callback fails. The container is not returned in that
+// case and the error is a CallbackError.
+func WithTerminateOnCallbackError() GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.callbackError.terminate = true
+	})
+}
+
+// WithLogsOnCallbackError captures the last lines of the container logs into the CallbackError if any callback fails.
+// All the logs are captured if the tail is not positive.
+func WithLogsOnCallbackError(tail int) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.callbackError.captureLogs = true
+		o.callbackError.logsTail = tail
+	})
+}
+
+// runCallbacks runs the callbacks in order and stops at the first error.
+func runCallbacks(ctx context.Context, c Container, o genericContainerOptions) (Container, error) {
+	for i, cb := range o.callbacks {
+		err := cb.f(ctx, c, o.request)
+		if err == nil {
+			continue
+		}
+
+		if !o.callbackError.terminate && !o.callbackError.captureLogs {
+			return c, err
+		}
+
+		cbErr := &CallbackError{Index: i, Name: cb.name, Err: err}
+
+		// The callback could fail because the context is canceled, the logs and the termination must be done anyway.
+		ctx := context.WithoutCancel(ctx)
+
+		if o.callbackError.captureLogs {
+			logs, lErr := readLogs(ctx, c, o.callbackError.logsTail)
+			if lErr != nil {
+				logs = fmt.Sprintf("could not get logs: %s", lErr.Error())
+			}
+
+			cbErr.Logs = logs
+		}
+
+		if !o.callbackError.terminate {
+			return c, cbErr
+		}
+
+		if tErr := c.Terminate(ctx); tErr != nil {
+			return c, errors.Join(cbErr, &ContainerError{Name: o.request.Name, ID: c.GetContainerID(), Phase: PhaseTerminate, Err: tErr})
+		}
+
+		return nil, cbErr
+	}
+
+	return c, nil
+}
+
+// readLogs reads the last lines of the container logs, or all the logs if the tail is not positive.
+func readLogs(ctx context.Context, c Container, tail int) (string, error) {
+	r, err := c.Logs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if r == nil {
+		return "", nil
+	}
+
+	defer r.Close() // nolint: errcheck
+
+	var lines []string
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for s.Scan() {
+		lines = append(lines, s.Text())
+
+		if tail > 0 && len(lines) > tail {
+			lines = lines[1:]
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
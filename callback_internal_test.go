@@ -0,0 +1,122 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+func TestStartGenericContainer_CallbackError(t *testing.T) {
+	t.Parallel()
+
+	callbackErr := errors.New("callback error")
+	noop := func(context.Context, Container, ContainerRequest) error { return nil }
+	failure := func(context.Context, Container, ContainerRequest) error { return callbackErr }
+
+	testCases := []struct {
+		scenario          string
+		mockContainer     mock.ContainerMocker
+		options           []GenericContainerOption
+		expectedContainer bool
+		expectedError     string
+	}{
+		{
+			scenario:          "no options",
+			mockContainer:     mock.NopContainer,
+			options:           []GenericContainerOption{WithCallback(noop), WithCallback(failure)},
+			expectedContainer: true,
+			expectedError:     "callback error",
+		},
+		{
+			scenario: "terminate",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Terminate", testifymock.Anything).
+					Return(nil).Once()
+			}),
+			options: []GenericContainerOption{
+				WithCallback(noop),
+				WithNamedCallback("migration", failure),
+				WithTerminateOnCallbackError(),
+			},
+			expectedError: `callback #1 "migration" failed: callback error`,
+		},
+		{
+			scenario: "terminate error",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Terminate", testifymock.Anything).
+					Return(errors.New("terminate error")).Once()
+
+				c.On("GetContainerID").
+					Return("42").Once()
+			}),
+			options: []GenericContainerOption{
+				ContainerCallback(failure),
+				WithTerminateOnCallbackError(),
+			},
+			expectedContainer: true,
+			expectedError:     "callback #0 failed: callback error\ncould not stop container \"42\": terminate error",
+		},
+		{
+			scenario: "logs",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Logs", testifymock.Anything).
+					Return(io.NopCloser(strings.NewReader("line 1\nline 2\nline 3\n")), nil).Once()
+			}),
+			options: []GenericContainerOption{
+				WithNamedCallback("migration", failure),
+				WithLogsOnCallbackError(2),
+			},
+			expectedContainer: true,
+			expectedError:     "callback #0 \"migration\" failed: callback error\nlogs:\nline 2\nline 3",
+		},
+		{
+			scenario: "logs error and terminate",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Logs", testifymock.Anything).
+					Return(nil, errors.New("logs error")).Once()
+
+				c.On("Terminate", testifymock.Anything).
+					Return(nil).Once()
+			}),
+			options: []GenericContainerOption{
+				WithCallback(failure),
+				WithLogsOnCallbackError(0),
+				WithTerminateOnCallbackError(),
+			},
+			expectedError: "callback #0 failed: callback error\nlogs:\ncould not get logs: logs error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			mc := tc.mockContainer(t)
+
+			opts := append([]GenericContainerOption{
+				fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+					return mc, nil
+				}),
+			}, tc.options...)
+
+			c, err := StartGenericContainer(context.Background(), ContainerRequest{}, opts...)
+
+			if tc.expectedContainer {
+				assert.Same(t, mc, c)
+			} else {
+				assert.Nil(t, c)
+			}
+
+			assert.EqualError(t, err, tc.expectedError)
+			assert.ErrorIs(t, err, callbackErr)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Package main provides a command that removes the containers left behind by go.nhat.io/testcontainers-extra, see
+// testcontainers.RemoveOrphans.
+//
+// Usage:
+//
+//	testcontainers-cleanup [flags]
+//
+// At least one of -older-than, -run-id, -package or -label is required, so that the resources of the test runs that
+// are still running are not removed by accident.
+//
+// Flags:
+//
+//	-older-than duration   only remove the resources that are older than the duration, for example, 1h
+//	-run-id string         only remove the resources of a test run
+//	-package string        only remove the containers started by the tests of a package
+//	-label key=value       only remove the resources that have the label, can be repeated
+//	-all                   also remove the containers, the networks and the volumes of any testcontainers client
+//	-dry-run               only list the resources that would be removed
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"go.nhat.io/testcontainers-extra"
+)
+
+var (
+	errInvalidLabel = errors.New("label must be key=value or key")
+	errNoFilter     = errors.New("at least one of -older-than, -run-id, -package or -label is required")
+)
+
+type labelFlag []testcontainers.OrphanOption
+
+func (f *labelFlag) String() string {
+	return ""
+}
+
+func (f *labelFlag) Set(s string) error {
+	key, value, _ := strings.Cut(s, "=")
+	if key == "" {
+		return errInvalidLabel
+	}
+
+	*f = append(*f, testcontainers.WithOrphanLabel(key, value))
+
+	return nil
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("testcontainers-cleanup", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var labels labelFlag
+
+	olderThan := fs.Duration("older-than", 0, "only remove the resources that are older than the duration, for example, 1h")
+	runID := fs.String("run-id", "", "only remove the resources of a test run")
+	pkg := fs.String("package", "", "only remove the containers started by the tests of a package")
+	all := fs.Bool("all", false, "also remove the containers, the networks and the volumes of any testcontainers client")
+	dryRun := fs.Bool("dry-run", false, "only list the resources that would be removed")
+
+	fs.Var(&labels, "label", "only remove the resources that have the label, key=value or key, can be repeated")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *olderThan <= 0 && *runID == "" && *pkg == "" && len(labels) == 0 {
+		_, _ = fmt.Fprintf(stderr, "%s\n", errNoFilter.Error())
+
+		fs.Usage()
+
+		return 2
+	}
+
+	opts := append([]testcontainers.OrphanOption{
+		testcontainers.WithOrphanOlderThan(*olderThan),
+		testcontainers.WithOrphanRunID(*runID),
+		testcontainers.WithOrphanPackage(*pkg),
+	}, labels...)
+
+	if *all {
+		opts = append(opts, testcontainers.WithOrphanAllTestcontainers())
+	}
+
+	if *dryRun {
+		opts = append(opts, testcontainers.WithOrphanDryRun())
+	}
+
+	orphans, err := testcontainers.RemoveOrphans(ctx, opts...)
+
+	verb := "removed"
+
+	if *dryRun {
+		verb = "would remove"
+	}
+
+	for _, o := range orphans {
+		_, _ = fmt.Fprintf(stdout, "%s %s\n", verb, o)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "%s\n", err.Error())
+
+		return 1
+	}
+
+	return 0
+}
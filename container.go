@@ -3,11 +3,16 @@ package testcontainers
 import (
 	"context"
 	"fmt"
-	"sync"
+	"maps"
+	"slices"
+	"testing"
 
 	"github.com/testcontainers/testcontainers-go"
 )
 
+// ManagedLabel is the label of every container that is started by this package.
+const ManagedLabel = "io.nhat.testcontainers-extra"
+
 // GenericContainerOption is option for starting a new generic container.
 type GenericContainerOption interface {
 	applyOptions(o *genericContainerOptions)
@@ -20,105 +25,246 @@ func (f genericContainerOptionFunc) applyOptions(o *genericContainerOptions) {
 }
 
 type genericContainerOptions struct {
-	request      ContainerRequest
-	providerType testcontainers.ProviderType
-	callbacks    []ContainerCallback
+	request       ContainerRequest
+	providerType  testcontainers.ProviderType
+	callbacks     []containerCallback
+	callbackError callbackErrorOptions
+	retry         retryOptions
+	shared        *sharedOptions
+	persistent    persistentOptions
+	keepOnFailure testing.TB
+	origin        containerOrigin
+	provenance    bool
+	// createdHooks are called when the container is created, even if it could not be started.
+	createdHooks []func(c Container)
+
+	genericContainer func(ctx context.Context, req testcontainers.GenericContainerRequest) (Container, error)
+}
+
+// BatchOption is option for starting multiple generic containers at once.
+type BatchOption interface {
+	applyBatchOptions(o *batchOptions)
+}
+
+type batchOptionFunc func(o *batchOptions)
+
+func (f batchOptionFunc) applyBatchOptions(o *batchOptions) {
+	f(o)
+}
+
+type batchOptions struct {
+	rollback       bool
+	failFast       bool
+	maxConcurrency int
+	// test is the name of the test that starts the containers, if any.
+	test string
 }
 
 // StartGenericContainer starts a new generic container.
 func StartGenericContainer(ctx context.Context, request ContainerRequest, opts ...GenericContainerOption) (Container, error) {
-	originalName := request.Name
+	o := newGenericContainerOptions(request, opts)
+	o.origin = captureOrigin(o.origin.test)
+
+	c, _, err := startGenericContainer(ctx, request.Name, o)
 
+	return c, err
+}
+
+func newGenericContainerOptions(request ContainerRequest, opts []GenericContainerOption) genericContainerOptions {
 	o := genericContainerOptions{
-		request: request,
+		request:          request,
+		persistent:       newPersistentOptions(),
+		genericContainer: testcontainers.GenericContainer,
 	}
 
 	for _, opt := range opts {
 		opt.applyOptions(&o)
 	}
 
+	return o
+}
+
+// startGenericContainer starts a new generic container and returns the phase in which it fails.
+func startGenericContainer(ctx context.Context, originalName string, o genericContainerOptions) (Container, Phase, error) {
+	r, lock, err := newGenericContainerRequest(ctx, &o)
+	if err != nil {
+		return nil, PhaseStart, err
+	}
+
+	if lock != nil {
+		defer lock.unlockGuard()
+	}
+
+	o.request.Name = originalName
+
+	c, err := genericContainerWithRetry(ctx, o, r)
+	c = wrapContainer(c, lock, originalName, o)
+
+	if c != nil {
+		for _, h := range o.createdHooks {
+			h(c)
+		}
+	}
+
+	if err != nil {
+		return c, PhaseStart, err
+	}
+
+	if c, err = runCallbacks(ctx, c, o); err != nil {
+		return c, PhaseCallback, err
+	}
+
+	return c, "", nil
+}
+
+// newGenericContainerRequest prepares the request for starting the container. The shared container is locked until the
+// container is started.
+func newGenericContainerRequest(ctx context.Context, o *genericContainerOptions) (testcontainers.GenericContainerRequest, *sharedLock, error) {
 	r := testcontainers.GenericContainerRequest{
 		ContainerRequest: o.request,
 		Started:          true,
 		ProviderType:     o.providerType,
 	}
-	o.request.Name = originalName
 
-	c, err := testcontainers.GenericContainer(ctx, r)
-	if err != nil {
-		return c, err
+	// The fingerprint is only required by the shared and the persistent containers, the other containers are started
+	// without the label if the request could not be fingerprinted.
+	fp, err := fingerprintRequest(&r.ContainerRequest)
+	if err != nil && (o.shared != nil || o.persistent.enabled) {
+		return r, nil, err
+	}
+
+	r.Labels = maps.Clone(r.Labels)
+
+	if r.Labels == nil {
+		r.Labels = make(map[string]string)
 	}
 
-	for _, f := range o.callbacks {
-		if err := f(ctx, c, o.request); err != nil {
-			return c, err
+	r.Labels[ManagedLabel] = "true"
+
+	if err == nil {
+		r.Labels[FingerprintLabel] = fp
+	}
+
+	if hc := nativeHealthConfig(r.ContainerRequest); hc != nil {
+		r.ConfigModifier = withHealthConfig(r.ContainerRequest, hc)
+	}
+
+	if o.provenance {
+		addProvenanceLabels(r.Labels, o.origin)
+	}
+
+	if o.persistent.enabled {
+		if err := persistContainer(ctx, &r, o.persistent, fp); err != nil {
+			return r, nil, err
 		}
 	}
 
-	return c, nil
+	if o.keepOnFailure != nil {
+		keepOnFailure(&r)
+	}
+
+	var lock *sharedLock
+
+	if o.shared != nil {
+		if lock, err = shareContainer(ctx, &r, o.shared, fp); err != nil {
+			return r, nil, err
+		}
+	}
+
+	r.LifecycleHooks = append(slices.Clone(r.LifecycleHooks), leaks.hooks(r.Name, o.origin))
+
+	return r, lock, nil
+}
+
+// wrapContainer changes how the container is terminated following the options.
+func wrapContainer(c Container, lock *sharedLock, originalName string, o genericContainerOptions) Container {
+	if lock != nil {
+		c = lock.attach(c)
+	}
+
+	if c == nil {
+		return nil
+	}
+
+	if o.keepOnFailure != nil {
+		c = &keptOnFailureContainer{Container: c, tb: o.keepOnFailure, name: originalName}
+	}
+
+	if o.persistent.enabled {
+		c = &persistentContainer{Container: c}
+	}
+
+	return c
 }
 
 // StartGenericContainerRequest is request for starting a new generic container.
 type StartGenericContainerRequest struct {
 	Request ContainerRequest
 	Options []GenericContainerOption
+	// DependsOn contains the names of the requests that must be started before this one.
+	DependsOn []string
+	// Priority decides the order of the requests in the same dependency level, the higher priority is started first.
+	Priority int
 }
 
 // StartGenericContainers starts multiple generic containers at once.
-func StartGenericContainers(ctx context.Context, requests ...StartGenericContainerRequest) (containers []Container, _ error) {
-	var (
-		wg sync.WaitGroup
-		mu sync.Mutex
-	)
+//
+// The error is an Errors that contains a ContainerError for every request that fails.
+//
+// Requests are started level by level following their dependencies, the requests in the same level are started in
+// parallel and the ones with higher priority are started first. A request is not started if any of its dependencies
+// fails.
+func StartGenericContainers(ctx context.Context, requests ...StartGenericContainerRequest) ([]Container, error) {
+	return StartGenericContainersWithOptions(ctx, requests)
+}
+
+// StartGenericContainersWithOptions starts multiple generic containers at once, see StartGenericContainers. The
+// containers are returned in the order of the requests.
+func StartGenericContainersWithOptions(ctx context.Context, requests []StartGenericContainerRequest, opts ...BatchOption) ([]Container, error) {
+	result, err := StartGenericContainerBatch(ctx, requests, opts...)
 
-	wg.Add(len(requests))
+	return result.Containers(), err
+}
 
-	containers = make([]Container, 0, len(requests))
-	errs := make(errorCollection, 0, len(requests))
+// StartGenericContainerBatch starts multiple generic containers at once, see StartGenericContainers. The result keeps
+// the container and the error of every request in the order of the requests.
+func StartGenericContainerBatch(ctx context.Context, requests []StartGenericContainerRequest, opts ...BatchOption) (StartedContainers, error) {
+	o := batchOptions{}
 
-	for _, r := range requests {
-		go func(r StartGenericContainerRequest) {
-			defer wg.Done()
+	for _, opt := range opts {
+		opt.applyBatchOptions(&o)
+	}
 
-			c, sErr := StartGenericContainer(ctx, r.Request, r.Options...)
+	levels, err := dependencyLevels(requests)
+	if err != nil {
+		return nil, fmt.Errorf("could not start containers: %w", err)
+	}
 
-			mu.Lock()
-			defer mu.Unlock()
+	b := newBatch(ctx, requests, o)
+	defer b.cancel(nil)
 
-			if sErr != nil {
-				errs.Append(fmt.Errorf("could not start container %q: %w", r.Request.Name, sErr))
-			}
+	b.start(levels)
 
-			if c != nil {
-				containers = append(containers, c)
-			}
-		}(r)
+	if o.rollback && b.hasErrors() {
+		// The context could be canceled by the caller, the containers must be terminated anyway.
+		b.rollback(context.WithoutCancel(ctx))
 	}
 
-	wg.Wait()
-
-	return containers, errs.AsError()
+	return b.results, b.err()
 }
 
-// StopGenericContainers stops multiple containers at once.
+// StopGenericContainers stops multiple containers at once. The nil containers are skipped.
+//
+// The error is an Errors that contains a ContainerError for every container that could not be stopped.
 func StopGenericContainers(ctx context.Context, containers ...Container) error {
-	var wg sync.WaitGroup
-
-	wg.Add(len(containers))
-
-	errs := make(errorCollection, 0, len(containers))
-
-	for _, c := range containers {
-		go func(c testcontainers.Container) {
-			defer wg.Done()
+	return StopGenericContainersWithOptions(ctx, containers)
+}
 
-			if err := c.Terminate(ctx); err != nil {
-				errs.Append(fmt.Errorf("could not stop container %q: %w", c.GetContainerID(), err))
-			}
-		}(c)
-	}
+// StopGenericContainersWithOptions stops multiple containers at once, see StopGenericContainers.
+func StopGenericContainersWithOptions(ctx context.Context, containers []Container, opts ...StopOption) error {
+	o := newStopOptions(opts)
 
-	wg.Wait()
+	errs := stopContainers(ctx, containerStopLevels(containers, o.reverseOrder), o)
 
 	return errs.AsError()
 }
@@ -127,5 +273,5 @@ func StopGenericContainers(ctx context.Context, containers ...Container) error {
 type ContainerCallback func(ctx context.Context, c Container, r ContainerRequest) error
 
 func (c ContainerCallback) applyOptions(o *genericContainerOptions) {
-	o.callbacks = append(o.callbacks, c)
+	o.callbacks = append(o.callbacks, containerCallback{f: c})
 }
@@ -0,0 +1,730 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+type fakeGenericContainer func(ctx context.Context, req testcontainers.GenericContainerRequest) (Container, error)
+
+func (f fakeGenericContainer) applyOptions(o *genericContainerOptions) {
+	o.genericContainer = f
+}
+
+// startRecorder records the order in which the containers are started.
+type startRecorder struct {
+	mu      sync.Mutex
+	started []string
+}
+
+func (r *startRecorder) start(err error) fakeGenericContainer {
+	return func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.started = append(r.started, req.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &mock.Container{}, nil
+	}
+}
+
+func TestStartGenericContainers_DependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainers(context.Background(),
+		StartGenericContainerRequest{
+			Request:   ContainerRequest{Name: "app"},
+			Options:   []GenericContainerOption{r.start(nil)},
+			DependsOn: []string{"postgres"},
+		},
+		StartGenericContainerRequest{
+			Request: ContainerRequest{Name: "postgres"},
+			Options: []GenericContainerOption{r.start(nil)},
+		},
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, containers, 2)
+	assert.Equal(t, []string{"postgres", "app"}, r.started)
+}
+
+func TestStartGenericContainers_DependencyFailed(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainers(context.Background(),
+		StartGenericContainerRequest{
+			Request:   ContainerRequest{Name: "app"},
+			Options:   []GenericContainerOption{r.start(nil)},
+			DependsOn: []string{"postgres"},
+		},
+		StartGenericContainerRequest{
+			Request: ContainerRequest{Name: "postgres"},
+			Options: []GenericContainerOption{r.start(errors.New("start error"))},
+		},
+		StartGenericContainerRequest{
+			Request: ContainerRequest{Name: "kafka"},
+			Options: []GenericContainerOption{r.start(nil)},
+		},
+	)
+
+	expected := "could not start container \"postgres\": start error\n" +
+		"could not start container \"app\": dependency failed: \"postgres\""
+
+	assert.EqualError(t, err, expected)
+	assert.Len(t, containers, 1)
+	assert.ElementsMatch(t, []string{"postgres", "kafka"}, r.started)
+}
+
+func TestStartGenericContainers_DependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainers(context.Background(),
+		StartGenericContainerRequest{
+			Request:   ContainerRequest{Name: "app"},
+			Options:   []GenericContainerOption{r.start(nil)},
+			DependsOn: []string{"postgres"},
+		},
+		StartGenericContainerRequest{
+			Request:   ContainerRequest{Name: "postgres"},
+			Options:   []GenericContainerOption{r.start(nil)},
+			DependsOn: []string{"app"},
+		},
+	)
+
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Empty(t, containers)
+	assert.Empty(t, r.started)
+}
+
+func TestStartGenericContainersWithOptions_Rollback(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+
+	postgres := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(nil).Once()
+	})(t)
+
+	kafka := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(errors.New("terminate error")).Once()
+
+		c.On("GetContainerID").
+			Return("kafka").Twice()
+	})(t)
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+						defer close(started)
+
+						return postgres, nil
+					}),
+				},
+			},
+			{
+				Request: ContainerRequest{Name: "kafka"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(ctx context.Context, _ testcontainers.GenericContainerRequest) (Container, error) {
+						<-ctx.Done()
+
+						return kafka, ctx.Err()
+					}),
+				},
+			},
+			{
+				Request: ContainerRequest{Name: "app"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+						<-started
+
+						return nil, errors.New("start error")
+					}),
+				},
+			},
+		},
+		WithRollback(),
+	)
+
+	assert.Nil(t, containers)
+	assert.ErrorContains(t, err, `could not start container "app": start error`)
+	assert.ErrorContains(t, err, `could not start container "kafka": context canceled`)
+	assert.ErrorContains(t, err, `could not stop container "kafka": terminate error`)
+}
+
+func TestStartGenericContainersWithOptions_RollbackSkipsNextLevels(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request:   ContainerRequest{Name: "app"},
+				Options:   []GenericContainerOption{r.start(nil)},
+				DependsOn: []string{"postgres"},
+			},
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{r.start(errors.New("start error"))},
+			},
+		},
+		WithRollback(),
+	)
+
+	assert.Nil(t, containers)
+	expected := "could not start container \"postgres\": start error\n" +
+		"canceled because of the failure above:\n" +
+		"could not start container \"app\": context canceled"
+
+	assert.EqualError(t, err, expected)
+	assert.Equal(t, []string{"postgres"}, r.started)
+}
+
+func TestStartGenericContainersWithOptions_FailFast(t *testing.T) {
+	t.Parallel()
+
+	startErr := errors.New("start error")
+	r := &startRecorder{}
+
+	var cause error
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(ctx context.Context, _ testcontainers.GenericContainerRequest) (Container, error) {
+						<-ctx.Done()
+
+						cause = context.Cause(ctx)
+
+						return nil, ctx.Err()
+					}),
+				},
+			},
+			{
+				Request: ContainerRequest{Name: "kafka"},
+				Options: []GenericContainerOption{r.start(startErr)},
+			},
+			{
+				Request:   ContainerRequest{Name: "app"},
+				Options:   []GenericContainerOption{r.start(nil)},
+				DependsOn: []string{"postgres"},
+			},
+		},
+		WithFailFast(),
+	)
+
+	expected := "could not start container \"kafka\": start error\n" +
+		"canceled because of the failure above:\n" +
+		"could not start container \"postgres\": context canceled\n" +
+		"could not start container \"app\": context canceled"
+
+	assert.Empty(t, containers)
+	assert.EqualError(t, err, expected)
+	assert.ErrorIs(t, err, startErr)
+	assert.ErrorIs(t, cause, startErr)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"kafka"}, r.started)
+
+	var ffErr *FailFastError
+
+	require.ErrorAs(t, err, &ffErr)
+	assert.Len(t, ffErr.Canceled, 2)
+
+	var cErr *ContainerError
+
+	require.ErrorAs(t, ffErr.Canceled[0], &cErr)
+	assert.ErrorIs(t, err, cErr)
+	assert.ErrorIs(t, cErr, context.Canceled)
+}
+
+func TestStartGenericContainersWithOptions_FailFastNoError(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{r.start(nil)},
+			},
+			{
+				Request:   ContainerRequest{Name: "app"},
+				Options:   []GenericContainerOption{r.start(nil)},
+				DependsOn: []string{"postgres"},
+			},
+		},
+		WithFailFast(),
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, containers, 2)
+	assert.Equal(t, []string{"postgres", "app"}, r.started)
+}
+
+func TestStartGenericContainersWithOptions_Priority(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "app"},
+				Options: []GenericContainerOption{r.start(nil)},
+			},
+			{
+				Request:  ContainerRequest{Name: "elasticsearch"},
+				Options:  []GenericContainerOption{r.start(nil)},
+				Priority: 10,
+			},
+			{
+				Request:  ContainerRequest{Name: "postgres"},
+				Options:  []GenericContainerOption{r.start(nil)},
+				Priority: 5,
+			},
+			{
+				Request:  ContainerRequest{Name: "kafka"},
+				Options:  []GenericContainerOption{r.start(nil)},
+				Priority: 5,
+			},
+			{
+				Request:  ContainerRequest{Name: "minio"},
+				Options:  []GenericContainerOption{r.start(nil)},
+				Priority: math.MinInt,
+			},
+			{
+				Request:  ContainerRequest{Name: "redis"},
+				Options:  []GenericContainerOption{r.start(nil)},
+				Priority: math.MaxInt,
+			},
+		},
+		WithMaxConcurrency(1),
+	)
+
+	assert.NoError(t, err)
+	assert.Len(t, containers, 6)
+	assert.Equal(t, []string{"redis", "elasticsearch", "postgres", "kafka", "app", "minio"}, r.started)
+}
+
+func TestStartGenericContainersWithOptions_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+	)
+
+	start := fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return &mock.Container{}, nil
+	})
+
+	requests := make([]StartGenericContainerRequest, 0, 6)
+
+	for range 6 {
+		requests = append(requests, StartGenericContainerRequest{
+			Options: []GenericContainerOption{start},
+		})
+	}
+
+	containers, err := StartGenericContainersWithOptions(context.Background(), requests, WithMaxConcurrency(2))
+
+	assert.NoError(t, err)
+	assert.Len(t, containers, 6)
+	assert.Equal(t, 2, peak)
+}
+
+func TestStartGenericContainersWithOptions_MaxConcurrencyFailFast(t *testing.T) {
+	t.Parallel()
+
+	r := &startRecorder{}
+
+	containers, err := StartGenericContainersWithOptions(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request:  ContainerRequest{Name: "postgres"},
+				Options:  []GenericContainerOption{r.start(errors.New("start error"))},
+				Priority: 1,
+			},
+			{
+				Request: ContainerRequest{Name: "kafka"},
+				Options: []GenericContainerOption{r.start(nil)},
+			},
+		},
+		WithMaxConcurrency(1),
+		WithFailFast(),
+	)
+
+	expected := "could not start container \"postgres\": start error\n" +
+		"canceled because of the failure above:\n" +
+		"could not start container \"kafka\": context canceled"
+
+	assert.Empty(t, containers)
+	assert.EqualError(t, err, expected)
+	assert.Equal(t, []string{"postgres"}, r.started)
+}
+
+func TestStartGenericContainerBatch(t *testing.T) {
+	t.Parallel()
+
+	startErr := errors.New("start error")
+	r := &startRecorder{}
+
+	result, err := StartGenericContainerBatch(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request:   ContainerRequest{Name: "app"},
+				Options:   []GenericContainerOption{r.start(nil), WithNamePrefix("test")},
+				DependsOn: []string{"postgres"},
+			},
+			{
+				Request: ContainerRequest{Name: "kafka"},
+				Options: []GenericContainerOption{r.start(startErr)},
+			},
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{r.start(nil), WithNamePrefix("test"), WithNameSuffix("1")},
+			},
+		},
+	)
+
+	require.Len(t, result, 3)
+
+	assert.EqualError(t, err, `could not start container "kafka": start error`)
+	assert.Equal(t, []string{"app", "kafka", "postgres"}, []string{result[0].Name, result[1].Name, result[2].Name})
+	assert.Equal(t, []string{"test_app", "kafka", "test_postgres_1"}, []string{result[0].ContainerName, result[1].ContainerName, result[2].ContainerName})
+	assert.ElementsMatch(t, []string{"test_postgres_1", "kafka", "test_app"}, r.started)
+
+	assert.NotNil(t, result.Get("app"))
+	assert.Same(t, result.Get("postgres"), result.Get("test_postgres_1"))
+	assert.Nil(t, result.Get("kafka"))
+	assert.ErrorIs(t, result.Err("kafka"), startErr)
+	assert.Equal(t, []Container{result[0].Container, result[2].Container}, result.Containers())
+}
+
+func TestStartGenericContainerBatch_Errors(t *testing.T) {
+	t.Parallel()
+
+	startErr := errors.New("start error")
+	callbackErr := errors.New("callback error")
+	r := &startRecorder{}
+
+	app := mock.MockContainer(func(c *mock.Container) {
+		c.On("GetContainerID").
+			Return("42").Once()
+	})(t)
+
+	_, err := StartGenericContainerBatch(context.Background(),
+		[]StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{r.start(startErr)},
+			},
+			{
+				Request: ContainerRequest{Name: "app"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+						return app, nil
+					}),
+					WithCallback(func(context.Context, Container, ContainerRequest) error {
+						return callbackErr
+					}),
+				},
+			},
+		},
+	)
+
+	var errs Errors
+
+	require.ErrorAs(t, err, &errs)
+
+	expected := []*ContainerError{
+		{Name: "postgres", Phase: PhaseStart, Err: startErr},
+		{Name: "app", ID: "42", Phase: PhaseCallback, Err: callbackErr},
+	}
+
+	assert.ElementsMatch(t, expected, errs.ContainerErrors())
+	assert.ErrorIs(t, err, startErr)
+	assert.ErrorIs(t, err, callbackErr)
+}
+
+func TestStopGenericContainers(t *testing.T) {
+	t.Parallel()
+
+	terminateErr := errors.New("terminate error")
+
+	postgres := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(nil).Once()
+	})(t)
+
+	app := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(terminateErr).Once()
+
+		c.On("GetContainerID").
+			Return("42").Once()
+	})(t)
+
+	err := StopGenericContainers(context.Background(), postgres, app)
+
+	expected := Errors{&ContainerError{ID: "42", Phase: PhaseTerminate, Err: terminateErr}}
+
+	assert.Equal(t, expected, err)
+	assert.ErrorIs(t, err, terminateErr)
+}
+
+// attempts returns a fake generic container that fails with the errors in order, then succeeds. The containers are
+// returned together with the errors.
+func attempts(called *int, containers []Container, errs ...error) fakeGenericContainer {
+	return func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+		i := *called
+		*called++
+
+		if i >= len(errs) {
+			return &mock.Container{}, nil
+		}
+
+		if i < len(containers) {
+			return containers[i], errs[i]
+		}
+
+		return nil, errs[i]
+	}
+}
+
+func TestStartGenericContainer_Retry(t *testing.T) {
+	t.Parallel()
+
+	transientErr := errors.New("port is already allocated")
+
+	testCases := []struct {
+		scenario       string
+		mockContainer  mock.ContainerMocker
+		errors         []error
+		maxRetries     int
+		expectedCalled int
+		expectedError  string
+	}{
+		{
+			scenario:       "no retry",
+			errors:         []error{transientErr},
+			expectedCalled: 1,
+			expectedError:  "port is already allocated",
+		},
+		{
+			scenario: "success after retries",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Terminate", testifymock.Anything).
+					Return(nil).Once()
+			}),
+			errors:         []error{transientErr, transientErr},
+			maxRetries:     3,
+			expectedCalled: 3,
+		},
+		{
+			scenario:       "max retries exceeded",
+			errors:         []error{transientErr, transientErr, transientErr},
+			maxRetries:     2,
+			expectedCalled: 3,
+			expectedError:  "gave up after 3 attempts: port is already allocated",
+		},
+		{
+			scenario:       "not transient",
+			errors:         []error{transientErr, errors.New("not found")},
+			maxRetries:     2,
+			expectedCalled: 2,
+			expectedError:  "gave up after 2 attempts: not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			called := 0
+
+			var containers []Container
+
+			if tc.mockContainer != nil {
+				containers = []Container{nil, tc.mockContainer(t)}
+			}
+
+			_, err := StartGenericContainer(context.Background(), ContainerRequest{},
+				attempts(&called, containers, tc.errors...),
+				WithRetry(tc.maxRetries, ConstantBackoff(time.Millisecond)),
+			)
+
+			assert.Equal(t, tc.expectedCalled, called)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestStartGenericContainer_RetryCleanupError(t *testing.T) {
+	t.Parallel()
+
+	transientErr := errors.New("port is already allocated")
+	terminateErr := errors.New("terminate error")
+
+	failed := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(terminateErr).Once()
+
+		c.On("GetContainerID").
+			Return("42").Once()
+	})(t)
+
+	called := 0
+
+	_, err := StartGenericContainer(context.Background(), ContainerRequest{},
+		attempts(&called, []Container{failed}, transientErr),
+		WithRetry(3, nil),
+	)
+
+	assert.Equal(t, 1, called)
+	assert.ErrorIs(t, err, transientErr)
+	assert.ErrorIs(t, err, terminateErr)
+}
+
+func TestStartGenericContainer_RetryFileReader(t *testing.T) {
+	t.Parallel()
+
+	transientErr := errors.New("port is already allocated")
+
+	var contents []string
+
+	request := ContainerRequest{
+		Files: []ContainerFile{{Reader: strings.NewReader("hello"), ContainerFilePath: "/hello.txt"}},
+	}
+
+	_, err := StartGenericContainer(context.Background(), request,
+		fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			// The files are copied the same way as testcontainers does.
+			content, err := io.ReadAll(req.Files[0].Reader)
+			require.NoError(t, err)
+
+			contents = append(contents, string(content))
+
+			if len(contents) < 3 {
+				return nil, transientErr
+			}
+
+			return &mock.Container{}, nil
+		}),
+		WithRetry(3, nil),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"hello", "hello", "hello"}, contents)
+}
+
+func TestStartGenericContainer_FingerprintLabel(t *testing.T) {
+	t.Parallel()
+
+	request := ContainerRequest{
+		Image:  "postgres:16",
+		Labels: map[string]string{"team": "a"},
+	}
+
+	expected, err := Fingerprint(request, WithImageTag("17"))
+	require.NoError(t, err)
+
+	var labels map[string]string
+
+	_, err = StartGenericContainer(context.Background(), request,
+		WithImageTag("17"),
+		fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			labels = req.Labels
+
+			return nil, errors.New("start error")
+		}),
+	)
+	require.EqualError(t, err, "start error")
+
+	assert.Equal(t, map[string]string{"team": "a", ManagedLabel: "true", FingerprintLabel: expected}, labels)
+	assert.Equal(t, map[string]string{"team": "a"}, request.Labels, "the labels of the request must not change")
+}
+
+func TestStartGenericContainer_FingerprintError(t *testing.T) {
+	t.Parallel()
+
+	request := ContainerRequest{
+		Image:  "postgres:16",
+		Labels: map[string]string{"team": "a"},
+		Files:  []ContainerFile{{HostFilePath: filepath.Join(t.TempDir(), "unknown.sql"), ContainerFilePath: "/init.sql"}},
+	}
+
+	var labels map[string]string
+
+	_, err := StartGenericContainer(context.Background(), request,
+		fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			labels = req.Labels
+
+			return nil, errors.New("start error")
+		}),
+	)
+	require.EqualError(t, err, "start error", "the container is started without the fingerprint")
+
+	assert.Equal(t, map[string]string{"team": "a", ManagedLabel: "true"}, labels)
+	assert.Equal(t, map[string]string{"team": "a"}, request.Labels)
+
+	_, err = StartGenericContainer(context.Background(), request,
+		WithSharedContainerDir(t.TempDir()),
+		fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+			assert.Fail(t, "the shared container should not be started")
+
+			return nil, nil
+		}),
+	)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
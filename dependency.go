@@ -0,0 +1,162 @@
+package testcontainers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// dependencyLevels groups the requests into levels so that every request only depends on requests in the previous
+// levels. Requests in the same level do not depend on each other and can be started at the same time. The levels
+// contain indexes of the requests.
+func dependencyLevels(requests []StartGenericContainerRequest) ([][]int, error) {
+	indexes := make(map[string]int, len(requests))
+	duplicates := make(map[string]struct{})
+
+	for i, r := range requests {
+		if r.Request.Name == "" {
+			continue
+		}
+
+		if _, ok := indexes[r.Request.Name]; ok {
+			duplicates[r.Request.Name] = struct{}{}
+
+			continue
+		}
+
+		indexes[r.Request.Name] = i
+	}
+
+	inDegrees := make([]int, len(requests))
+	dependents := make([][]int, len(requests))
+	dependencies := make([][]int, len(requests))
+
+	for i, r := range requests {
+		for _, dep := range r.DependsOn {
+			if _, ok := duplicates[dep]; ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrAmbiguousDependency, r.Request.Name, dep)
+			}
+
+			j, ok := indexes[dep]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, r.Request.Name, dep)
+			}
+
+			if slices.Contains(dependencies[i], j) {
+				continue
+			}
+
+			inDegrees[i]++
+			dependents[j] = append(dependents[j], i)
+			dependencies[i] = append(dependencies[i], j)
+		}
+	}
+
+	var (
+		levels  [][]int
+		current []int
+		visited int
+	)
+
+	for i, d := range inDegrees {
+		if d == 0 {
+			current = append(current, i)
+		}
+	}
+
+	for len(current) > 0 {
+		levels = append(levels, current)
+		visited += len(current)
+
+		var next []int
+
+		for _, i := range current {
+			for _, j := range dependents[i] {
+				inDegrees[j]--
+
+				if inDegrees[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+
+		slices.Sort(next)
+
+		current = next
+	}
+
+	if visited != len(requests) {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(findDependencyCycle(requests, dependencies, inDegrees), " -> "))
+	}
+
+	return levels, nil
+}
+
+// findDependencyCycle returns the names of the requests that form a cycle. It only looks at the requests that could not
+// be leveled, which are the ones with a positive in-degree.
+func findDependencyCycle(requests []StartGenericContainerRequest, dependencies [][]int, inDegrees []int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	states := make([]int, len(requests))
+	path := make([]int, 0, len(requests))
+
+	var visit func(i int) []int
+
+	visit = func(i int) []int {
+		states[i] = visiting
+		path = append(path, i)
+
+		for _, j := range dependencies[i] {
+			switch states[j] {
+			case visiting:
+				start := slices.Index(path, j)
+				cycle := slices.Clone(path[start:])
+
+				return append(cycle, j)
+
+			case unvisited:
+				if cycle := visit(j); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		states[i] = visited
+		path = path[:len(path)-1]
+
+		return nil
+	}
+
+	for i, d := range inDegrees {
+		if d == 0 || states[i] != unvisited {
+			continue
+		}
+
+		if cycle := visit(i); cycle != nil {
+			names := make([]string, 0, len(cycle))
+
+			for _, j := range cycle {
+				names = append(names, fmt.Sprintf("%q", requests[j].Request.Name))
+			}
+
+			return names
+		}
+	}
+
+	return nil
+}
+
+// failedDependency returns the first dependency of the request that failed to start.
+func failedDependency(r StartGenericContainerRequest, failed map[string]struct{}) (string, bool) {
+	for _, dep := range r.DependsOn {
+		if _, ok := failed[dep]; ok {
+			return dep, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,86 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyLevels(t *testing.T) {
+	t.Parallel()
+
+	request := func(name string, dependsOn ...string) StartGenericContainerRequest {
+		return StartGenericContainerRequest{
+			Request:   ContainerRequest{Name: name},
+			DependsOn: dependsOn,
+		}
+	}
+
+	testCases := []struct {
+		scenario       string
+		requests       []StartGenericContainerRequest
+		expectedLevels [][]int
+		expectedError  string
+	}{
+		{
+			scenario: "no requests",
+		},
+		{
+			scenario:       "no dependencies",
+			requests:       []StartGenericContainerRequest{request("postgres"), request("kafka"), request("")},
+			expectedLevels: [][]int{{0, 1, 2}},
+		},
+		{
+			scenario: "dependencies",
+			requests: []StartGenericContainerRequest{
+				request("app", "postgres", "kafka"),
+				request("kafka", "zookeeper"),
+				request("postgres"),
+				request("zookeeper"),
+				request("migration", "postgres", "postgres"),
+			},
+			expectedLevels: [][]int{{2, 3}, {1, 4}, {0}},
+		},
+		{
+			scenario:      "unknown dependency",
+			requests:      []StartGenericContainerRequest{request("app", "postgres")},
+			expectedError: `unknown dependency: "app" depends on "postgres"`,
+		},
+		{
+			scenario:      "ambiguous dependency",
+			requests:      []StartGenericContainerRequest{request("app", "postgres"), request("postgres"), request("postgres")},
+			expectedError: `ambiguous dependency: "app" depends on "postgres"`,
+		},
+		{
+			scenario:      "self dependency",
+			requests:      []StartGenericContainerRequest{request("app", "app")},
+			expectedError: `dependency cycle: "app" -> "app"`,
+		},
+		{
+			scenario: "cycle",
+			requests: []StartGenericContainerRequest{
+				request("postgres"),
+				request("app", "kafka", "postgres"),
+				request("kafka", "zookeeper"),
+				request("zookeeper", "app"),
+			},
+			expectedError: `dependency cycle: "app" -> "kafka" -> "zookeeper" -> "app"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := dependencyLevels(tc.requests)
+
+			assert.Equal(t, tc.expectedLevels, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
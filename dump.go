@@ -0,0 +1,178 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	defaultDumpLogsTail = 100
+	dumpTimeout         = 30 * time.Second
+)
+
+// FailureDumpOption is option for dumping a container when a test fails.
+type FailureDumpOption func(o *failureDumpOptions)
+
+type failureDumpOptions struct {
+	logsTail int
+	dir      string
+}
+
+// WithDumpLogsTail sets the number of log lines in the dump. All the logs are dumped if n is not positive. The default
+// value is 100.
+func WithDumpLogsTail(n int) FailureDumpOption {
+	return func(o *failureDumpOptions) {
+		o.logsTail = n
+	}
+}
+
+// WithDumpDir writes the dump to a file in a directory of the test inside the given directory, instead of the test
+// output.
+func WithDumpDir(dir string) FailureDumpOption {
+	return func(o *failureDumpOptions) {
+		o.dir = dir
+	}
+}
+
+// WithFailureDump dumps the state, the details and the last logs of the container when the test fails. Nothing is
+// dumped if the test passes.
+//
+// The dump runs in a cleanup of the test, so the container must be terminated in a cleanup that is registered before
+// starting the container, as StartGenericContainerT does.
+func WithFailureDump(tb testing.TB, opts ...FailureDumpOption) GenericContainerOption {
+	o := failureDumpOptions{
+		logsTail: defaultDumpLogsTail,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return genericContainerOptionFunc(func(co *genericContainerOptions) {
+		co.createdHooks = append(co.createdHooks, func(c Container) {
+			tb.Cleanup(func() {
+				if !tb.Failed() {
+					return
+				}
+
+				dumpContainer(tb, c, o)
+			})
+		})
+	})
+}
+
+func dumpContainer(tb testing.TB, c Container, o failureDumpOptions) {
+	tb.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), dumpTimeout)
+	defer cancel()
+
+	name, dump := describeContainer(ctx, c, o.logsTail)
+
+	if o.dir == "" {
+		tb.Logf("container %q:\n%s", name, dump)
+
+		return
+	}
+
+	dir := filepath.Join(o.dir, sanitizeFileName(tb.Name()))
+	file := filepath.Join(dir, sanitizeFileName(name)+".log")
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		tb.Logf("could not create dump directory %q: %s\ncontainer %q:\n%s", dir, err.Error(), name, dump)
+
+		return
+	}
+
+	if err := os.WriteFile(file, []byte(dump), 0o600); err != nil {
+		tb.Logf("could not write dump file %q: %s\ncontainer %q:\n%s", file, err.Error(), name, dump)
+
+		return
+	}
+
+	tb.Logf("container %q is dumped to %s", name, file)
+}
+
+// describeContainer returns the name of the container and describes its details and its last logs.
+func describeContainer(ctx context.Context, c Container, logsTail int) (string, string) {
+	name := c.GetContainerID()
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("id: %s\n", c.GetContainerID()))
+
+	if info, err := c.Inspect(ctx); err != nil {
+		sb.WriteString(fmt.Sprintf("inspect: could not inspect container: %s\n", err.Error()))
+	} else if info != nil {
+		if info.Name != "" {
+			name = strings.TrimPrefix(info.Name, "/")
+		}
+
+		describeInspect(&sb, info)
+	}
+
+	if logs, err := readLogs(ctx, c, logsTail); err != nil {
+		sb.WriteString(fmt.Sprintf("logs: could not get logs: %s\n", err.Error()))
+	} else {
+		sb.WriteString("logs:\n")
+		sb.WriteString(logs)
+		sb.WriteRune('\n')
+	}
+
+	return name, sb.String()
+}
+
+func describeInspect(sb *strings.Builder, info *container.InspectResponse) {
+	if info.Config != nil {
+		sb.WriteString(fmt.Sprintf("image: %s\n", info.Config.Image))
+	}
+
+	if info.ContainerJSONBase != nil {
+		sb.WriteString(fmt.Sprintf("restart count: %d\n", info.RestartCount))
+
+		if s := info.State; s != nil {
+			sb.WriteString(fmt.Sprintf("state: %s (exit code: %d, oom killed: %t)\n", s.Status, s.ExitCode, s.OOMKilled))
+			sb.WriteString(fmt.Sprintf("started at: %s\n", s.StartedAt))
+
+			if s.FinishedAt != "" && !strings.HasPrefix(s.FinishedAt, "0001-") {
+				sb.WriteString(fmt.Sprintf("finished at: %s\n", s.FinishedAt))
+			}
+
+			if s.Error != "" {
+				sb.WriteString(fmt.Sprintf("error: %s\n", s.Error))
+			}
+
+			if s.Health != nil {
+				sb.WriteString(fmt.Sprintf("health: %s (failing streak: %d)\n", s.Health.Status, s.Health.FailingStreak))
+			}
+		}
+	}
+
+	if info.NetworkSettings != nil && len(info.NetworkSettings.Ports) > 0 {
+		ports := make([]string, 0, len(info.NetworkSettings.Ports))
+
+		for p, bindings := range info.NetworkSettings.Ports {
+			for _, b := range bindings {
+				ports = append(ports, fmt.Sprintf("%s -> %s:%s", p, b.HostIP, b.HostPort))
+			}
+		}
+
+		sort.Strings(ports)
+
+		sb.WriteString(fmt.Sprintf("ports: %s\n", strings.Join(ports, ", ")))
+	}
+}
+
+var unsafeFileNameChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", " ", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+func sanitizeFileName(s string) string {
+	return unsafeFileNameChars.Replace(s)
+}
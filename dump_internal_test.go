@@ -0,0 +1,120 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+func mockDumpedContainer(c *mock.Container) {
+	c.On("GetContainerID").
+		Return("42")
+
+	c.On("Inspect", testifymock.Anything).
+		Return(&container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{
+				Name:         "/postgres",
+				RestartCount: 1,
+				State: &container.State{
+					Status:     "running",
+					StartedAt:  "2024-01-01T00:00:00Z",
+					FinishedAt: "0001-01-01T00:00:00Z",
+					Health:     &container.Health{Status: "unhealthy", FailingStreak: 3},
+				},
+			},
+			Config: &container.Config{Image: "postgres:12-alpine"},
+			NetworkSettings: &container.NetworkSettings{
+				NetworkSettingsBase: container.NetworkSettingsBase{
+					Ports: nat.PortMap{
+						"5432/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "32768"}},
+					},
+				},
+			},
+		}, nil).Once()
+
+	c.On("Logs", testifymock.Anything).
+		Return(io.NopCloser(strings.NewReader("line 1\nline 2\nline 3\n")), nil).Once()
+
+	c.On("Terminate", testifymock.Anything).
+		Return(nil).Once()
+}
+
+const expectedDump = "id: 42\n" +
+	"image: postgres:12-alpine\n" +
+	"restart count: 1\n" +
+	"state: running (exit code: 0, oom killed: false)\n" +
+	"started at: 2024-01-01T00:00:00Z\n" +
+	"health: unhealthy (failing streak: 3)\n" +
+	"ports: 5432/tcp -> 0.0.0.0:32768\n" +
+	"logs:\n" +
+	"line 2\n" +
+	"line 3\n"
+
+func startDumpedContainer(tb *fakeTB, mc Container, fail bool, opts ...FailureDumpOption) {
+	tb.run(func() {
+		StartGenericContainerT(tb, ContainerRequest{Name: "postgres"},
+			fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+				return mc, nil
+			}),
+			WithFailureDump(tb, append([]FailureDumpOption{WithDumpLogsTail(2)}, opts...)...),
+		)
+
+		if fail {
+			tb.Errorf("test failed")
+		}
+	})
+}
+
+func TestWithFailureDump_Passed(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+
+	mc := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(nil).Once()
+	})(t)
+
+	startDumpedContainer(tb, mc, false)
+
+	assert.Empty(t, tb.logs)
+}
+
+func TestWithFailureDump_Log(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+
+	startDumpedContainer(tb, mock.MockContainer(mockDumpedContainer)(t), true)
+
+	assert.Equal(t, []string{"container \"postgres\":\n" + expectedDump}, tb.logs)
+}
+
+func TestWithFailureDump_Dir(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+	dir := t.TempDir()
+
+	startDumpedContainer(tb, mock.MockContainer(mockDumpedContainer)(t), true, WithDumpDir(dir))
+
+	file := filepath.Join(dir, "TestFake", "postgres.log")
+	actual, err := os.ReadFile(file) // nolint: gosec
+
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedDump, string(actual))
+	assert.Equal(t, []string{`container "postgres" is dumped to ` + file}, tb.logs)
+}
@@ -6,55 +6,63 @@ import (
 	"os"
 	"regexp"
 	"strings"
-
-	"github.com/docker/go-connections/nat"
 )
 
 var setEnv = os.Setenv
 
 // PopulateHostPortEnv sets the hostname and public port for each exposed port.
 var PopulateHostPortEnv = ContainerCallback(func(ctx context.Context, c Container, r ContainerRequest) error {
+	envVars, err := hostPortEnvVars(ctx, c, r.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range envVars {
+		if err := setEnv(v.name, v.value); err != nil {
+			return fmt.Errorf("could not set env var %q: %w", v.name, err)
+		}
+	}
+
+	return nil
+})
+
+type envVar struct {
+	name  string
+	value string
+}
+
+// hostPortEnvVars returns the env vars of the hostname and public port for each exposed port.
+func hostPortEnvVars(ctx context.Context, c Container, containerName string) ([]envVar, error) {
 	ports, err := c.Ports(ctx)
 	if err != nil {
-		return fmt.Errorf("could not get container %q ports: %w", r.Name, err)
+		return nil, fmt.Errorf("could not get container %q ports: %w", containerName, err)
 	}
 
 	if len(ports) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	ip, err := c.Host(ctx)
 	if err != nil {
-		return fmt.Errorf("could not get container %q ip: %w", r.Name, err)
+		return nil, fmt.Errorf("could not get container %q ip: %w", containerName, err)
 	}
 
+	envVars := make([]envVar, 0, 2*len(ports))
+
 	for p, bindings := range ports {
 		for _, b := range bindings {
-			if err := setEnvVar(r.Name, p, "HOST", ip); err != nil {
-				return err
-			}
-
-			if err := setEnvVar(r.Name, p, "PORT", b.HostPort); err != nil {
-				return err
-			}
+			envVars = append(envVars,
+				envVar{name: envVarName(containerName, p.Port(), "HOST"), value: ip},
+				envVar{name: envVarName(containerName, p.Port(), "PORT"), value: b.HostPort},
+			)
 		}
 	}
 
-	return nil
-})
+	return envVars, nil
+}
 
 var alphaNum = regexp.MustCompile("[^a-zA-Z0-9]+")
 
 func envVarName(parts ...string) string {
 	return strings.ToUpper(alphaNum.ReplaceAllString(strings.Join(parts, "_"), "_"))
 }
-
-func setEnvVar(containerName string, containerPort nat.Port, suffix, value string) error {
-	envVar := envVarName(containerName, containerPort.Port(), suffix)
-
-	if err := setEnv(envVar, value); err != nil {
-		return fmt.Errorf("could not set env var %q: %w", envVar, err)
-	}
-
-	return nil
-}
@@ -2,8 +2,147 @@ package testcontainers
 
 import (
 	"fmt"
+	"strings"
 )
 
+const (
+	// ErrUnknownDependency indicates that a request depends on a request that does not exist.
+	ErrUnknownDependency batchError = "unknown dependency"
+	// ErrAmbiguousDependency indicates that a request depends on a name that is shared by several requests.
+	ErrAmbiguousDependency batchError = "ambiguous dependency"
+	// ErrDependencyCycle indicates that the requests depend on each other.
+	ErrDependencyCycle batchError = "dependency cycle"
+	// ErrDependencyFailed indicates that a request is not started because one of its dependencies failed.
+	ErrDependencyFailed batchError = "dependency failed"
+)
+
+type batchError string
+
+// Error satisfies error interface.
+func (e batchError) Error() string {
+	return string(e)
+}
+
+// Container phases.
+const (
+	PhaseStart     Phase = "start"
+	PhaseCallback  Phase = "callback"
+	PhaseTerminate Phase = "terminate"
+)
+
+// Phase is the phase in which an operation on a container fails.
+type Phase string
+
+// ContainerError is an error of an operation on a container.
+type ContainerError struct {
+	// Name is the name of the request, it is empty if the container is not started by a request.
+	Name string
+	// ID is the ID of the container, it is empty if the container could not be created.
+	ID string
+	// Phase is the phase in which the operation fails.
+	Phase Phase
+	// Err is the cause.
+	Err error
+}
+
+// Error satisfies error interface.
+func (e *ContainerError) Error() string {
+	if e.Phase == PhaseTerminate {
+		id := e.ID
+		if id == "" {
+			id = e.Name
+		}
+
+		return fmt.Sprintf("could not stop container %q: %s", id, e.Err.Error())
+	}
+
+	return fmt.Sprintf("could not start container %q: %s", e.Name, e.Err.Error())
+}
+
+// Unwrap returns the cause.
+func (e *ContainerError) Unwrap() error {
+	return e.Err
+}
+
+// Errors contains multiple errors. It supports errors.Is and errors.As for every error.
+type Errors []error
+
+// Error satisfies error interface.
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// ContainerErrors returns all the container errors, including the ones in the nested errors and the canceled starts of
+// FailFastError.
+func (e Errors) ContainerErrors() []*ContainerError {
+	return collectContainerErrors(nil, e)
+}
+
+func collectContainerErrors(result []*ContainerError, err error) []*ContainerError {
+	switch e := err.(type) { // nolint: errorlint
+	case *ContainerError:
+		return append(result, e)
+
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			result = collectContainerErrors(result, err)
+		}
+
+		return result
+
+	case interface{ Unwrap() error }:
+		return collectContainerErrors(result, e.Unwrap())
+	}
+
+	return result
+}
+
+// FailFastError is returned when the starts are canceled because one of the requests fails.
+type FailFastError struct {
+	// Cause is the failure that cancels the other starts.
+	Cause error
+	// Canceled contains the errors of the starts that are canceled or skipped because of the cause.
+	Canceled []error
+}
+
+// Error satisfies error interface.
+func (e *FailFastError) Error() string {
+	if len(e.Canceled) == 0 {
+		return e.Cause.Error()
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(e.Cause.Error())
+	sb.WriteString("\ncanceled because of the failure above:")
+
+	for _, err := range e.Canceled {
+		sb.WriteRune('\n')
+		sb.WriteString(err.Error())
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the cause followed by the canceled starts, so errors.Is and errors.As work for all of them.
+func (e *FailFastError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Canceled)+1)
+	errs = append(errs, e.Cause)
+
+	return append(errs, e.Canceled...)
+}
+
 type errorCollection []error
 
 func (e *errorCollection) Append(err error) {
@@ -15,11 +154,5 @@ func (e *errorCollection) AsError() error {
 		return nil
 	}
 
-	err := (*e)[0]
-
-	for i := 1; i < len(*e); i++ {
-		err = fmt.Errorf("%w\n%s", err, (*e)[i].Error())
-	}
-
-	return err
+	return Errors(*e)
 }
@@ -0,0 +1,115 @@
+package testcontainers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.nhat.io/testcontainers-extra"
+)
+
+func TestContainerError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("error")
+
+	testCases := []struct {
+		scenario string
+		error    *testcontainers.ContainerError
+		expected string
+	}{
+		{
+			scenario: "start",
+			error:    &testcontainers.ContainerError{Name: "postgres", Phase: testcontainers.PhaseStart, Err: cause},
+			expected: `could not start container "postgres": error`,
+		},
+		{
+			scenario: "callback",
+			error:    &testcontainers.ContainerError{Name: "postgres", ID: "42", Phase: testcontainers.PhaseCallback, Err: cause},
+			expected: `could not start container "postgres": error`,
+		},
+		{
+			scenario: "terminate with id",
+			error:    &testcontainers.ContainerError{Name: "postgres", ID: "42", Phase: testcontainers.PhaseTerminate, Err: cause},
+			expected: `could not stop container "42": error`,
+		},
+		{
+			scenario: "terminate without id",
+			error:    &testcontainers.ContainerError{Name: "postgres", Phase: testcontainers.PhaseTerminate, Err: cause},
+			expected: `could not stop container "postgres": error`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tc.error, tc.expected)
+			assert.ErrorIs(t, tc.error, cause)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	startErr := &testcontainers.ContainerError{Name: "postgres", Phase: testcontainers.PhaseStart, Err: context.DeadlineExceeded}
+	callbackErr := &testcontainers.ContainerError{Name: "app", Phase: testcontainers.PhaseCallback, Err: errors.New("migration error")}
+	canceledErr := &testcontainers.ContainerError{Name: "kafka", Phase: testcontainers.PhaseStart, Err: context.Canceled}
+	terminateErr := &testcontainers.ContainerError{ID: "42", Phase: testcontainers.PhaseTerminate, Err: errors.New("terminate error")}
+
+	err := testcontainers.Errors{
+		&testcontainers.FailFastError{Cause: startErr, Canceled: []error{canceledErr}},
+		callbackErr,
+		terminateErr,
+	}
+
+	expected := "could not start container \"postgres\": context deadline exceeded\n" +
+		"canceled because of the failure above:\n" +
+		"could not start container \"kafka\": context canceled\n" +
+		"could not start container \"app\": migration error\n" +
+		"could not stop container \"42\": terminate error"
+
+	assert.EqualError(t, err, expected)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, callbackErr)
+	assert.ErrorIs(t, err, terminateErr)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, canceledErr)
+	assert.ErrorIs(t, errors.Join(errors.New("other error"), err), terminateErr)
+
+	var cErr *testcontainers.ContainerError
+
+	require.ErrorAs(t, err, &cErr)
+	assert.Equal(t, startErr, cErr)
+
+	var errs testcontainers.Errors
+
+	require.ErrorAs(t, errors.Join(err), &errs)
+	assert.Equal(t, []*testcontainers.ContainerError{startErr, canceledErr, callbackErr, terminateErr}, errs.ContainerErrors())
+}
+
+func TestFailFastError_Canceled(t *testing.T) {
+	t.Parallel()
+
+	startErr := errors.New("could not create network")
+	canceledErr := &testcontainers.ContainerError{Name: "kafka", Phase: testcontainers.PhaseStart, Err: context.Canceled}
+
+	err := fmt.Errorf("wrapped: %w", &testcontainers.FailFastError{
+		Cause:    startErr,
+		Canceled: []error{errors.New("skipped"), canceledErr},
+	})
+
+	assert.ErrorIs(t, err, startErr)
+	assert.ErrorIs(t, err, canceledErr)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var cErr *testcontainers.ContainerError
+
+	require.ErrorAs(t, err, &cErr)
+	assert.Same(t, canceledErr, cErr)
+}
@@ -0,0 +1,291 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// FingerprintLabel is the label of the container that contains the fingerprint of its request.
+const FingerprintLabel = "io.nhat.testcontainers-extra.fingerprint"
+
+const (
+	// fingerprintVersion changes when the fingerprint of the same request changes.
+	fingerprintVersion = 2
+	// fingerprintContainerNamePrefix is the prefix of the name of the containers that are named after the fingerprint.
+	fingerprintContainerNamePrefix = "testcontainers-extra-"
+)
+
+// Fingerprint returns a stable hash of the request after applying the options. The fingerprint covers the fields that
+// change the container: the image or the dockerfile, the entrypoint, the command, the environment variables, the
+// exposed ports, the files, the mounts, the networks, the type of the wait strategy and the health check that is run by
+// Docker. The other fields, such as the name, the labels, the callbacks or the log consumers, are ignored.
+//
+// The content of the files is part of the fingerprint, the host files and directories are read and so are the readers.
+// A reader that is consumed by the fingerprint cannot be used to start the container anymore.
+func Fingerprint(request ContainerRequest, opts ...GenericContainerOption) (string, error) {
+	o := newGenericContainerOptions(request, opts)
+
+	return fingerprintRequest(&o.request)
+}
+
+// fingerprintRequest returns the fingerprint of the request. The readers of the files are replaced with the content
+// that is read so that the request can still be used to start the container.
+func fingerprintRequest(r *ContainerRequest) (string, error) {
+	files, err := fingerprintFiles(r)
+	if err != nil {
+		return "", fmt.Errorf("could not fingerprint request: %w", err)
+	}
+
+	exposedPorts := slices.Clone(r.ExposedPorts)
+	slices.Sort(exposedPorts)
+
+	data, err := json.Marshal(fingerprint{
+		Version:        fingerprintVersion,
+		Image:          r.Image,
+		ImagePlatform:  r.ImagePlatform,
+		Context:        r.Context,
+		Dockerfile:     r.Dockerfile,
+		BuildArgs:      r.BuildArgs,
+		Entrypoint:     r.Entrypoint,
+		Cmd:            r.Cmd,
+		Env:            r.Env,
+		ExposedPorts:   exposedPorts,
+		Files:          files,
+		Mounts:         fingerprintMounts(r.Mounts),
+		Tmpfs:          r.Tmpfs,
+		User:           r.User,
+		WorkingDir:     r.WorkingDir,
+		Privileged:     r.Privileged,
+		Networks:       r.Networks,
+		NetworkAliases: r.NetworkAliases,
+		WaitingFor:     fmt.Sprintf("%T", r.WaitingFor),
+		HealthCheck:    nativeHealthConfig(*r),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not fingerprint request: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func fingerprintFiles(r *ContainerRequest) ([]fingerprintFile, error) {
+	if len(r.Files) == 0 {
+		return nil, nil
+	}
+
+	// The files are cloned because the readers are replaced.
+	r.Files = slices.Clone(r.Files)
+	files := make([]fingerprintFile, 0, len(r.Files))
+
+	for i, f := range r.Files {
+		var (
+			content []byte
+			err     error
+		)
+
+		if f.Reader != nil {
+			content, err = io.ReadAll(f.Reader)
+			r.Files[i].Reader = bytes.NewReader(content)
+		} else {
+			content, err = readHostPath(f.HostFilePath)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %q: %w", f.ContainerFilePath, err)
+		}
+
+		sum := sha256.Sum256(content)
+
+		files = append(files, fingerprintFile{
+			Path:    f.ContainerFilePath,
+			Mode:    f.FileMode,
+			Content: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	return files, nil
+}
+
+// fingerprintMounts resolves the mounts the same way as testcontainers, the options of the sources are compared by
+// value and not by their addresses.
+func fingerprintMounts(mounts testcontainers.ContainerMounts) []fingerprintMount {
+	if len(mounts) == 0 {
+		return nil
+	}
+
+	result := make([]fingerprintMount, 0, len(mounts))
+
+	for _, m := range mounts {
+		fm := fingerprintMount{
+			Target:   m.Target.Target(),
+			ReadOnly: m.ReadOnly,
+		}
+
+		if m.Source != nil {
+			fm.Type = m.Source.Type()
+			fm.Source = m.Source.Source()
+		}
+
+		switch s := m.Source.(type) {
+		case testcontainers.VolumeMounter:
+			fm.VolumeOptions = s.GetVolumeOptions()
+
+		case testcontainers.TmpfsMounter:
+			fm.TmpfsOptions = s.GetTmpfsOptions()
+
+		case testcontainers.ImageMounter:
+			fm.ImageOptions = s.ImageOptions()
+
+		case testcontainers.BindMounter: // nolint: staticcheck
+			fm.BindOptions = s.GetBindOptions()
+		}
+
+		result = append(result, fm)
+	}
+
+	return result
+}
+
+// readHostPath reads the content of a host file. A directory, which testcontainers copies recursively, is read as the
+// relative path, the mode and the content of every entry, in lexical order.
+func readHostPath(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return os.ReadFile(path)
+	}
+
+	var buf bytes.Buffer
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(&buf, "%s\x00%o\x00", filepath.ToSlash(rel), info.Mode())
+
+		if d.Type().IsRegular() {
+			content, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+
+			sum := sha256.Sum256(content)
+
+			buf.WriteString(hex.EncodeToString(sum[:]))
+		}
+
+		buf.WriteByte('\n')
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fingerprintContainerName returns the name of the container of a request that has no name.
+func fingerprintContainerName(fp string) string {
+	return fingerprintContainerNamePrefix + fp[:16]
+}
+
+// ContainerFingerprint returns the fingerprint of the request of a running container, which is read from its label.
+// It is empty if the container does not have the label.
+func ContainerFingerprint(ctx context.Context, c Container) (string, error) {
+	info, err := c.Inspect(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not inspect container: %w", err)
+	}
+
+	if info.Config == nil {
+		return "", nil
+	}
+
+	return info.Config.Labels[FingerprintLabel], nil
+}
+
+// MatchFingerprint tells whether a running container is started with the same request, it compares the fingerprint
+// label of the container with the fingerprint of the request after applying the options.
+func MatchFingerprint(ctx context.Context, c Container, request ContainerRequest, opts ...GenericContainerOption) (bool, error) {
+	expected, err := Fingerprint(request, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	actual, err := ContainerFingerprint(ctx, c)
+	if err != nil {
+		return false, err
+	}
+
+	return actual == expected, nil
+}
+
+type fingerprint struct {
+	Version        int                     `json:"version"`
+	Image          string                  `json:"image,omitempty"`
+	ImagePlatform  string                  `json:"image_platform,omitempty"`
+	Context        string                  `json:"context,omitempty"`
+	Dockerfile     string                  `json:"dockerfile,omitempty"`
+	BuildArgs      map[string]*string      `json:"build_args,omitempty"`
+	Entrypoint     []string                `json:"entrypoint,omitempty"`
+	Cmd            []string                `json:"cmd,omitempty"`
+	Env            map[string]string       `json:"env,omitempty"`
+	ExposedPorts   []string                `json:"exposed_ports,omitempty"`
+	Files          []fingerprintFile       `json:"files,omitempty"`
+	Mounts         []fingerprintMount      `json:"mounts,omitempty"`
+	Tmpfs          map[string]string       `json:"tmpfs,omitempty"`
+	User           string                  `json:"user,omitempty"`
+	WorkingDir     string                  `json:"working_dir,omitempty"`
+	Privileged     bool                    `json:"privileged,omitempty"`
+	Networks       []string                `json:"networks,omitempty"`
+	NetworkAliases map[string][]string     `json:"network_aliases,omitempty"`
+	WaitingFor     string                  `json:"waiting_for"`
+	HealthCheck    *container.HealthConfig `json:"health_check,omitempty"`
+}
+
+type fingerprintFile struct {
+	Path    string `json:"path"`
+	Mode    int64  `json:"mode"`
+	Content string `json:"content"`
+}
+
+type fingerprintMount struct {
+	Type          testcontainers.MountType `json:"type"`
+	Source        string                   `json:"source"`
+	Target        string                   `json:"target"`
+	ReadOnly      bool                     `json:"read_only,omitempty"`
+	BindOptions   *mount.BindOptions       `json:"bind_options,omitempty"`
+	VolumeOptions *mount.VolumeOptions     `json:"volume_options,omitempty"`
+	TmpfsOptions  *mount.TmpfsOptions      `json:"tmpfs_options,omitempty"`
+	ImageOptions  *mount.ImageOptions      `json:"image_options,omitempty"`
+}
@@ -0,0 +1,326 @@
+package testcontainers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"go.nhat.io/testcontainers-extra"
+	"go.nhat.io/testcontainers-extra/mock"
+	extrawait "go.nhat.io/testcontainers-extra/wait"
+)
+
+func TestFingerprint_Stable(t *testing.T) {
+	t.Parallel()
+
+	first, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+		Name:         "postgres",
+		Image:        "postgres:16",
+		Env:          map[string]string{"POSTGRES_USER": "test", "POSTGRES_DB": "test"},
+		ExposedPorts: []string{"5432/tcp", "8080/tcp"},
+		Labels:       map[string]string{"team": "a"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp"),
+	})
+	require.NoError(t, err)
+
+	second, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+		Name:         "another",
+		Image:        "postgres:16",
+		Env:          map[string]string{"POSTGRES_DB": "test", "POSTGRES_USER": "test"},
+		ExposedPorts: []string{"8080/tcp", "5432/tcp"},
+		Labels:       map[string]string{"team": "b"},
+		WaitingFor:   wait.ForListeningPort("8080/tcp"),
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, second)
+}
+
+func TestFingerprint_Changes(t *testing.T) {
+	t.Parallel()
+
+	request := testcontainers.ContainerRequest{
+		Image: "postgres:16",
+		Env:   map[string]string{"POSTGRES_USER": "test"},
+	}
+
+	expected, err := testcontainers.Fingerprint(request)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario string
+		request  testcontainers.ContainerRequest
+		opts     []testcontainers.GenericContainerOption
+	}{
+		{
+			scenario: "image tag option",
+			request:  request,
+			opts:     []testcontainers.GenericContainerOption{testcontainers.WithImageTag("17")},
+		},
+		{
+			scenario: "env",
+			request:  testcontainers.ContainerRequest{Image: "postgres:16", Env: map[string]string{"POSTGRES_USER": "other"}},
+		},
+		{
+			scenario: "cmd",
+			request:  testcontainers.ContainerRequest{Image: "postgres:16", Env: request.Env, Cmd: []string{"postgres", "-c", "fsync=off"}},
+		},
+		{
+			scenario: "ports",
+			request:  testcontainers.ContainerRequest{Image: "postgres:16", Env: request.Env, ExposedPorts: []string{"5432/tcp"}},
+		},
+		{
+			scenario: "wait strategy type",
+			request:  testcontainers.ContainerRequest{Image: "postgres:16", Env: request.Env, WaitingFor: wait.ForLog("ready")},
+		},
+		{
+			scenario: "files",
+			request: testcontainers.ContainerRequest{Image: "postgres:16", Env: request.Env, Files: []testcontainers.ContainerFile{
+				{Reader: strings.NewReader("CREATE TABLE t;"), ContainerFilePath: "/docker-entrypoint-initdb.d/init.sql"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := testcontainers.Fingerprint(tc.request, tc.opts...)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, expected, actual)
+		})
+	}
+}
+
+func TestFingerprint_NativeHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := func(s *extrawait.HealthCheckStrategy) string {
+		t.Helper()
+
+		fp, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{Image: "postgres:16", WaitingFor: s})
+		require.NoError(t, err)
+
+		return fp
+	}
+
+	inProcess := fingerprint(extrawait.ForHealthCheckCmd("pg_isready"))
+
+	assert.Equal(t, inProcess, fingerprint(extrawait.ForHealthCheckCmd("pg_isready").WithRetries(5)))
+
+	native := fingerprint(extrawait.ForHealthCheckCmd("pg_isready").WithNativeHealthCheck())
+
+	assert.NotEqual(t, inProcess, native)
+	assert.Equal(t, native, fingerprint(extrawait.ForHealthCheckCmd("pg_isready").WithNativeHealthCheck()))
+	assert.NotEqual(t, native, fingerprint(extrawait.ForHealthCheckCmd("pg_isready").WithRetries(5).WithNativeHealthCheck()))
+}
+
+func TestFingerprint_MountOptions(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := func(m tc.ContainerMount) string {
+		t.Helper()
+
+		fp, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+			Image:  "postgres:16",
+			Mounts: tc.ContainerMounts{m},
+		})
+		require.NoError(t, err)
+
+		return fp
+	}
+
+	volume := func(noCopy bool) tc.ContainerMount {
+		return tc.ContainerMount{
+			Source: tc.DockerVolumeMountSource{Name: "data", VolumeOptions: &mount.VolumeOptions{NoCopy: noCopy}},
+			Target: "/var/lib/postgresql/data",
+		}
+	}
+
+	tmpfs := func(size int64) tc.ContainerMount {
+		return tc.ContainerMount{
+			Source: tc.DockerTmpfsMountSource{TmpfsOptions: &mount.TmpfsOptions{SizeBytes: size}},
+			Target: "/tmp",
+		}
+	}
+
+	assert.Equal(t, fingerprint(volume(true)), fingerprint(volume(true)))
+	assert.NotEqual(t, fingerprint(volume(true)), fingerprint(volume(false)))
+	assert.Equal(t, fingerprint(tmpfs(1024)), fingerprint(tmpfs(1024)))
+	assert.NotEqual(t, fingerprint(tmpfs(1024)), fingerprint(tmpfs(2048)))
+	assert.NotEqual(t, fingerprint(volume(true)), fingerprint(tc.ContainerMount{
+		Source:   tc.DockerVolumeMountSource{Name: "data", VolumeOptions: &mount.VolumeOptions{NoCopy: true}},
+		Target:   "/var/lib/postgresql/data",
+		ReadOnly: true,
+	}))
+}
+
+func TestFingerprint_FileContent(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "init.sql")
+
+	fingerprint := func(content string) string {
+		t.Helper()
+
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+		fp, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+			Image: "postgres:16",
+			Files: []testcontainers.ContainerFile{{HostFilePath: file, ContainerFilePath: "/docker-entrypoint-initdb.d/init.sql"}},
+		})
+		require.NoError(t, err)
+
+		return fp
+	}
+
+	assert.Equal(t, fingerprint("CREATE TABLE t;"), fingerprint("CREATE TABLE t;"))
+	assert.NotEqual(t, fingerprint("CREATE TABLE t;"), fingerprint("CREATE TABLE u;"))
+}
+
+func TestFingerprint_Directory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "schema"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "init.sql"), []byte("CREATE TABLE t;"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema", "users.sql"), []byte("CREATE TABLE users;"), 0o600))
+
+	fingerprint := func() string {
+		t.Helper()
+
+		fp, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+			Image: "postgres:16",
+			Files: []testcontainers.ContainerFile{{HostFilePath: dir, ContainerFilePath: "/docker-entrypoint-initdb.d"}},
+		})
+		require.NoError(t, err)
+
+		return fp
+	}
+
+	first := fingerprint()
+
+	assert.Len(t, first, 64)
+	assert.Equal(t, first, fingerprint())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema", "users.sql"), []byte("CREATE TABLE accounts;"), 0o600))
+
+	changedContent := fingerprint()
+
+	assert.NotEqual(t, first, changedContent)
+
+	require.NoError(t, os.Rename(filepath.Join(dir, "schema", "users.sql"), filepath.Join(dir, "schema", "accounts.sql")))
+
+	assert.NotEqual(t, changedContent, fingerprint())
+}
+
+func TestFingerprint_FileError(t *testing.T) {
+	t.Parallel()
+
+	fp, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+		Image: "postgres:16",
+		Files: []testcontainers.ContainerFile{{HostFilePath: filepath.Join(t.TempDir(), "unknown.sql"), ContainerFilePath: "/init.sql"}},
+	})
+
+	assert.Empty(t, fp)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.ErrorContains(t, err, `could not fingerprint request: could not read file "/init.sql"`)
+}
+
+func TestMatchFingerprint(t *testing.T) {
+	t.Parallel()
+
+	request := testcontainers.ContainerRequest{Image: "postgres:16"}
+
+	fp, err := testcontainers.Fingerprint(request)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario      string
+		mockContainer mock.ContainerMocker
+		expected      bool
+		expectedError string
+	}{
+		{
+			scenario: "inspect error",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Inspect", testifymock.Anything).
+					Return(nil, errors.New("inspect error"))
+			}),
+			expectedError: "could not inspect container: inspect error",
+		},
+		{
+			scenario: "no label",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Inspect", testifymock.Anything).
+					Return(&container.InspectResponse{Config: &container.Config{}}, nil)
+			}),
+		},
+		{
+			scenario: "different",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Inspect", testifymock.Anything).
+					Return(&container.InspectResponse{Config: &container.Config{
+						Labels: map[string]string{testcontainers.FingerprintLabel: "42"},
+					}}, nil)
+			}),
+		},
+		{
+			scenario: "same",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Inspect", testifymock.Anything).
+					Return(&container.InspectResponse{Config: &container.Config{
+						Labels: map[string]string{testcontainers.FingerprintLabel: fp},
+					}}, nil)
+			}),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := testcontainers.MatchFingerprint(context.Background(), tc.mockContainer(t), request)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestFingerprint_ReaderIsConsumed(t *testing.T) {
+	t.Parallel()
+
+	r := strings.NewReader("CREATE TABLE t;")
+
+	_, err := testcontainers.Fingerprint(testcontainers.ContainerRequest{
+		Files: []testcontainers.ContainerFile{{Reader: r, ContainerFilePath: "/init.sql"}},
+	})
+	require.NoError(t, err)
+
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Empty(t, rest)
+}
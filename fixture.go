@@ -0,0 +1,202 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Fixture scopes.
+const (
+	// ScopeTest shares the container within a test, the container is terminated when the test completes.
+	ScopeTest Scope = iota
+	// ScopePackage shares the container with all the tests of the same package. The container is terminated when the
+	// fixtures are closed and all the tests using it complete.
+	ScopePackage
+	// ScopeProcess shares the container with all the tests in the process. The container is terminated when the
+	// fixtures are closed and all the tests using it complete.
+	ScopeProcess
+)
+
+// Scope is the lifetime of a fixture.
+type Scope int
+
+// String returns the name of the scope.
+func (s Scope) String() string {
+	switch s {
+	case ScopeTest:
+		return "test"
+	case ScopePackage:
+		return "package"
+	case ScopeProcess:
+		return "process"
+	}
+
+	return fmt.Sprintf("Scope(%d)", int(s))
+}
+
+type fixtureKey struct {
+	scope     Scope
+	namespace string
+	key       string
+}
+
+type fixture struct {
+	ready     chan struct{}
+	container Container
+	err       error
+	refs      int
+	kept      bool
+}
+
+// Fixtures is a registry of containers that are shared by the tests asking for the same key within a scope. The
+// containers are reference counted and terminated when the last user releases them.
+type Fixtures struct {
+	mu       sync.Mutex
+	fixtures map[fixtureKey]*fixture
+}
+
+// NewFixtures creates a new registry of fixtures.
+func NewFixtures() *Fixtures {
+	return &Fixtures{
+		fixtures: make(map[fixtureKey]*fixture),
+	}
+}
+
+// Acquire returns the container of the key within the scope, the container is started if it does not exist yet. The
+// container is released when the test completes. The test fails immediately if the container could not be started.
+func (f *Fixtures) Acquire(tb testing.TB, key string, scope Scope, request ContainerRequest, opts ...GenericContainerOption) Container {
+	tb.Helper()
+
+	k := fixtureKey{scope: scope, key: key}
+
+	switch scope {
+	case ScopeTest:
+		k.namespace = tb.Name()
+	case ScopePackage:
+		k.namespace = callerPackage(1)
+	case ScopeProcess:
+	}
+
+	fx, created := f.acquire(k)
+
+	tb.Cleanup(func() {
+		if err := f.release(context.Background(), k, fx); err != nil {
+			tb.Errorf("could not release fixture %q: %s", key, err.Error())
+		}
+	})
+
+	if created {
+		ctx, cancel := testContext(tb)
+		defer cancel()
+
+		fx.container, fx.err = StartGenericContainer(ctx, request, opts...)
+
+		if fx.err != nil {
+			f.forget(k, fx)
+		}
+
+		close(fx.ready)
+	}
+
+	<-fx.ready
+
+	if fx.err != nil {
+		tb.Fatalf("could not start fixture %q (%s scope): %s", key, scope, fx.err.Error())
+	}
+
+	return fx.container
+}
+
+func (f *Fixtures) acquire(k fixtureKey) (*fixture, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if fx, ok := f.fixtures[k]; ok {
+		fx.refs++
+
+		return fx, false
+	}
+
+	fx := &fixture{ready: make(chan struct{}), refs: 1}
+
+	// The registry keeps the containers of the package and process scopes alive until it is closed.
+	if k.scope != ScopeTest {
+		fx.refs++
+		fx.kept = true
+	}
+
+	f.fixtures[k] = fx
+
+	return fx, true
+}
+
+// forget removes the fixture so that the next acquisition starts a new container. The registry does not keep the
+// fixture anymore, so the container that is returned with the error is terminated when the last test releases it.
+func (f *Fixtures) forget(k fixtureKey, fx *fixture) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fixtures[k] == fx {
+		delete(f.fixtures, k)
+	}
+
+	if fx.kept {
+		fx.kept = false
+		fx.refs--
+	}
+}
+
+func (f *Fixtures) release(ctx context.Context, k fixtureKey, fx *fixture) error {
+	f.mu.Lock()
+
+	fx.refs--
+
+	if fx.refs > 0 {
+		f.mu.Unlock()
+
+		return nil
+	}
+
+	if f.fixtures[k] == fx {
+		delete(f.fixtures, k)
+	}
+
+	f.mu.Unlock()
+
+	<-fx.ready
+
+	if fx.container == nil {
+		return nil
+	}
+
+	return fx.container.Terminate(ctx)
+}
+
+// Close releases the containers of the package and process scopes that are kept alive by the registry. The containers
+// that are still used by some tests are terminated when the tests complete.
+func (f *Fixtures) Close(ctx context.Context) error {
+	f.mu.Lock()
+
+	kept := make(map[fixtureKey]*fixture)
+
+	for k, fx := range f.fixtures {
+		if fx.kept {
+			fx.kept = false
+			kept[k] = fx
+		}
+	}
+
+	f.mu.Unlock()
+
+	errs := make(errorCollection, 0)
+
+	for k, fx := range kept {
+		if err := f.release(ctx, k, fx); err != nil {
+			errs.Append(&ContainerError{Name: k.key, ID: fx.container.GetContainerID(), Phase: PhaseTerminate, Err: err})
+		}
+	}
+
+	return errs.AsError()
+}
@@ -0,0 +1,298 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+// fixtureStarter counts the started containers.
+type fixtureStarter struct {
+	mu      sync.Mutex
+	started int
+	err     error
+}
+
+func (s *fixtureStarter) start(t *testing.T, terminateErr error) fakeGenericContainer {
+	t.Helper()
+
+	return func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.started++
+
+		if s.err != nil {
+			return nil, s.err
+		}
+
+		return mock.MockContainer(func(c *mock.Container) {
+			c.On("Terminate", testifymock.Anything).
+				Return(terminateErr).Once()
+		})(t), nil
+	}
+}
+
+func TestFixtures_ScopeTest(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	var first, second, other Container
+
+	tb := &fakeTB{name: "TestFirst"}
+
+	tb.run(func() {
+		first = f.Acquire(tb, "postgres", ScopeTest, ContainerRequest{}, s.start(t, nil))
+		second = f.Acquire(tb, "postgres", ScopeTest, ContainerRequest{}, s.start(t, nil))
+	})
+
+	otherTB := &fakeTB{name: "TestSecond"}
+
+	otherTB.run(func() {
+		other = f.Acquire(otherTB, "postgres", ScopeTest, ContainerRequest{}, s.start(t, nil))
+	})
+
+	assert.Same(t, first, second)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, 2, s.started)
+	assert.False(t, tb.Failed())
+	assert.False(t, otherTB.Failed())
+	assert.Empty(t, f.fixtures)
+}
+
+func TestFixtures_ScopeProcess(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	var first, second Container
+
+	tb := &fakeTB{name: "TestFirst"}
+
+	tb.run(func() {
+		first = f.Acquire(tb, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+	})
+
+	otherTB := &fakeTB{name: "TestSecond"}
+
+	otherTB.run(func() {
+		second = f.Acquire(otherTB, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+	})
+
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, s.started)
+	assert.Len(t, f.fixtures, 1)
+
+	err := f.Close(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, f.fixtures)
+}
+
+func TestFixtures_ScopePackage(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	tb := &fakeTB{name: "TestFirst"}
+
+	tb.run(func() {
+		f.Acquire(tb, "kafka", ScopePackage, ContainerRequest{}, s.start(t, nil))
+	})
+
+	require.Len(t, f.fixtures, 1)
+
+	for k := range f.fixtures {
+		assert.Equal(t, "go.nhat.io/testcontainers-extra", k.namespace)
+	}
+
+	err := f.Close(context.Background())
+	require.NoError(t, err)
+}
+
+func TestFixtures_CloseWhileInUse(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	tb := &fakeTB{name: "TestFirst"}
+
+	tb.run(func() {
+		f.Acquire(tb, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+
+		err := f.Close(context.Background())
+		assert.NoError(t, err)
+
+		// The container is still used by the test.
+		assert.Len(t, f.fixtures, 1)
+	})
+
+	assert.Empty(t, f.fixtures)
+	assert.False(t, tb.Failed())
+}
+
+func TestFixtures_Parallel(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	const users = 10
+
+	var (
+		wg         sync.WaitGroup
+		containers = make([]Container, users)
+	)
+
+	for i := range users {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			tb := &fakeTB{}
+
+			tb.run(func() {
+				containers[i] = f.Acquire(tb, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, s.started)
+
+	for _, c := range containers {
+		assert.Same(t, containers[0], c)
+	}
+
+	err := f.Close(context.Background())
+	require.NoError(t, err)
+}
+
+func TestFixtures_StartError(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{err: errors.New("start error")}
+
+	tb := &fakeTB{}
+
+	tb.run(func() {
+		f.Acquire(tb, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+	})
+
+	assert.True(t, tb.Failed())
+	assert.Equal(t, `could not start fixture "kafka" (process scope): start error`, tb.fatal)
+	assert.Empty(t, f.fixtures)
+
+	// The next test starts a new container.
+	s.err = nil
+
+	otherTB := &fakeTB{}
+
+	otherTB.run(func() {
+		f.Acquire(otherTB, "kafka", ScopeProcess, ContainerRequest{}, s.start(t, nil))
+	})
+
+	assert.False(t, otherTB.Failed())
+	assert.Equal(t, 2, s.started)
+
+	err := f.Close(context.Background())
+	require.NoError(t, err)
+}
+
+func TestFixtures_StartErrorWithContainer(t *testing.T) {
+	t.Parallel()
+
+	for _, scope := range []Scope{ScopeTest, ScopePackage, ScopeProcess} {
+		t.Run(scope.String(), func(t *testing.T) {
+			t.Parallel()
+
+			f := NewFixtures()
+
+			c := mock.MockContainer(func(c *mock.Container) {
+				c.On("Terminate", testifymock.Anything).
+					Return(nil).Once()
+			})(t)
+
+			start := fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+				return c, errors.New("wait error")
+			})
+
+			tb := &fakeTB{}
+
+			tb.run(func() {
+				f.Acquire(tb, "kafka", scope, ContainerRequest{}, start)
+			})
+
+			assert.True(t, tb.Failed())
+			assert.Equal(t, `could not start fixture "kafka" (`+scope.String()+` scope): wait error`, tb.fatal)
+			assert.Empty(t, f.fixtures)
+
+			// The container is terminated once, when the test is cleaned up.
+			c.AssertNumberOfCalls(t, "Terminate", 1)
+
+			require.NoError(t, f.Close(context.Background()))
+			c.AssertNumberOfCalls(t, "Terminate", 1)
+		})
+	}
+}
+
+func TestFixtures_TerminateError(t *testing.T) {
+	t.Parallel()
+
+	f := NewFixtures()
+	s := &fixtureStarter{}
+
+	tb := &fakeTB{}
+
+	tb.run(func() {
+		f.Acquire(tb, "postgres", ScopeTest, ContainerRequest{}, s.start(t, errors.New("terminate error")))
+	})
+
+	assert.Equal(t, []string{`could not release fixture "postgres": terminate error`}, tb.errors)
+}
+
+func TestScope_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "test", ScopeTest.String())
+	assert.Equal(t, "package", ScopePackage.String())
+	assert.Equal(t, "process", ScopeProcess.String())
+	assert.Equal(t, "Scope(42)", Scope(42).String())
+}
+
+func TestFuncPackage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{name: "go.nhat.io/testcontainers-extra.TestFuncPackage.func1", expected: "go.nhat.io/testcontainers-extra"},
+		{name: "go.nhat.io/testcontainers-extra/wait.(*HealthCheckStrategy).WaitUntilReady", expected: "go.nhat.io/testcontainers-extra/wait"},
+		{name: "main.main", expected: "main"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, funcPackage(tc.name))
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package testcontainers
+
+import (
+	"errors"
+	"os"
+)
+
+func lockFile(*os.File, bool) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+func unlockFile(*os.File) error {
+	return errors.ErrUnsupported
+}
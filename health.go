@@ -0,0 +1,46 @@
+package testcontainers
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// nativeHealthCheck is a wait strategy that is run by Docker as the health check of the container, such as
+// wait.HealthCheckStrategy with WithNativeHealthCheck.
+type nativeHealthCheck interface {
+	NativeHealthConfig() *container.HealthConfig
+}
+
+// nativeHealthConfig returns the health config of the wait strategy of the request, or nil if the strategy is not run
+// by Docker. The strategies in wait.ForAll are looked up recursively, the first health config is used because a
+// container has only one health check.
+func nativeHealthConfig(r ContainerRequest) *container.HealthConfig {
+	return findNativeHealthConfig(r.WaitingFor)
+}
+
+func findNativeHealthConfig(s wait.Strategy) *container.HealthConfig {
+	switch s := s.(type) {
+	case nativeHealthCheck:
+		return s.NativeHealthConfig()
+
+	case *wait.MultiStrategy:
+		if s == nil {
+			return nil
+		}
+
+		for _, st := range s.Strategies {
+			if hc := findNativeHealthConfig(st); hc != nil {
+				return hc
+			}
+		}
+	}
+
+	return nil
+}
+
+// withHealthConfig sets the health config of the container.
+func withHealthConfig(r ContainerRequest, hc *container.HealthConfig) func(cfg *container.Config) {
+	return chainConfigModifier(r, func(cfg *container.Config) {
+		cfg.Healthcheck = hc
+	})
+}
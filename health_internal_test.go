@@ -0,0 +1,127 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// fakeNativeHealthCheck is a wait strategy that is run by Docker.
+type fakeNativeHealthCheck struct {
+	wait.Strategy
+
+	config *container.HealthConfig
+}
+
+func (s fakeNativeHealthCheck) NativeHealthConfig() *container.HealthConfig {
+	return s.config
+}
+
+func TestStartGenericContainer_NativeHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	hc := &container.HealthConfig{
+		Test:     []string{"CMD", "pg_isready"},
+		Interval: time.Second,
+		Retries:  3,
+	}
+
+	testCases := []struct {
+		scenario       string
+		request        ContainerRequest
+		expectedHealth *container.HealthConfig
+		expectedUser   string
+	}{
+		{
+			scenario: "not a native health check",
+			request: ContainerRequest{
+				WaitingFor: wait.ForLog("ready"),
+			},
+		},
+		{
+			scenario: "no health config",
+			request: ContainerRequest{
+				WaitingFor: fakeNativeHealthCheck{},
+			},
+		},
+		{
+			scenario: "health config",
+			request: ContainerRequest{
+				User:       "postgres",
+				WaitingFor: fakeNativeHealthCheck{config: hc},
+			},
+			expectedHealth: hc,
+			expectedUser:   "postgres",
+		},
+		{
+			scenario: "nested health config",
+			request: ContainerRequest{
+				User: "postgres",
+				WaitingFor: wait.ForAll(
+					wait.ForLog("ready"),
+					(*wait.MultiStrategy)(nil),
+					wait.ForAll(fakeNativeHealthCheck{}, fakeNativeHealthCheck{config: hc}),
+					fakeNativeHealthCheck{config: &container.HealthConfig{Test: []string{"CMD", "true"}}},
+				),
+			},
+			expectedHealth: hc,
+			expectedUser:   "postgres",
+		},
+		{
+			scenario: "nested without health config",
+			request: ContainerRequest{
+				WaitingFor: wait.ForAll(wait.ForLog("ready"), wait.ForAll(fakeNativeHealthCheck{})),
+			},
+		},
+		{
+			scenario: "health config with config modifier",
+			request: ContainerRequest{
+				WaitingFor: fakeNativeHealthCheck{config: hc},
+				ConfigModifier: func(cfg *container.Config) {
+					cfg.User = "root"
+				},
+			},
+			expectedHealth: hc,
+			expectedUser:   "root",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var req testcontainers.GenericContainerRequest
+
+			tc.request.Image = "postgres:16"
+
+			_, err := StartGenericContainer(context.Background(), tc.request,
+				fakeGenericContainer(func(_ context.Context, r testcontainers.GenericContainerRequest) (Container, error) {
+					req = r
+
+					return nil, errors.New("start error")
+				}),
+			)
+			require.EqualError(t, err, "start error")
+
+			if tc.expectedHealth == nil {
+				assert.Nil(t, req.ConfigModifier)
+
+				return
+			}
+
+			cfg := &container.Config{}
+
+			req.ConfigModifier(cfg)
+
+			assert.Equal(t, tc.expectedHealth, cfg.Healthcheck)
+			assert.Equal(t, tc.expectedUser, cfg.User)
+		})
+	}
+}
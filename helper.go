@@ -1,6 +1,7 @@
 package testcontainers
 
 import (
+	"runtime"
 	"strings"
 
 	"github.com/distribution/reference"
@@ -58,3 +59,31 @@ func changeImageTag(image string, tag string) string {
 
 	return reference.FamiliarString(named)
 }
+
+// callerPackage returns the import path of the package of the caller. The skip is the number of the stack frames to
+// skip, 0 identifies the caller of callerPackage.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return funcPackage(fn.Name())
+}
+
+// funcPackage returns the import path of the package of a function, for example, the package of
+// "go.nhat.io/testcontainers-extra.TestFunc.func1" is "go.nhat.io/testcontainers-extra".
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+
+	return name
+}
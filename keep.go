@@ -0,0 +1,117 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// KeepOnFailureLabel is the label of the containers that are kept running when their test fails.
+const KeepOnFailureLabel = "io.nhat.testcontainers-extra.keep-on-failure"
+
+// WithKeepOnFailure keeps the container running for debugging when the test fails, terminating the container does
+// nothing in that case. The commands for inspecting the container and the endpoints that PopulateHostPortEnv would set
+// are logged to the test output. The container is terminated as usual if the test passes.
+//
+// The reaper does not remove the container either, use RemoveKeptContainers to remove all the containers that are
+// kept this way.
+func WithKeepOnFailure(tb testing.TB) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.keepOnFailure = tb
+	})
+}
+
+// keepOnFailure prepares the request for keeping the container when the test fails.
+func keepOnFailure(r *testcontainers.GenericContainerRequest) {
+	r.Labels[KeepOnFailureLabel] = "true"
+	r.ConfigModifier = withoutReaperLabels(r.ContainerRequest)
+}
+
+// keptOnFailureContainer is a container that is kept running when the test fails.
+type keptOnFailureContainer struct {
+	Container
+
+	tb   testing.TB
+	name string
+}
+
+// Terminate terminates the container if the test passes.
+func (c *keptOnFailureContainer) Terminate(ctx context.Context, opts ...testcontainers.TerminateOption) error {
+	if !c.tb.Failed() {
+		return c.Container.Terminate(ctx, opts...)
+	}
+
+	c.tb.Logf("%s", describeKeptContainer(ctx, c.Container, c.name))
+	leaks.untrack(c.GetContainerID())
+
+	return nil
+}
+
+func describeKeptContainer(ctx context.Context, c Container, name string) string {
+	id := c.GetContainerID()
+
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "container %q (%s) is kept running because the test failed\n", name, id)
+	_, _ = fmt.Fprintf(&sb, "  docker logs -f %s\n", id)
+	_, _ = fmt.Fprintf(&sb, "  docker exec -it %s sh\n", id)
+
+	envVars, err := hostPortEnvVars(ctx, c, name)
+	if err != nil {
+		_, _ = fmt.Fprintf(&sb, "endpoints: %s\n", err.Error())
+	} else if len(envVars) > 0 {
+		slices.SortFunc(envVars, func(a, b envVar) int {
+			return strings.Compare(a.name, b.name)
+		})
+
+		sb.WriteString("endpoints:\n")
+
+		for _, v := range envVars {
+			_, _ = fmt.Fprintf(&sb, "  %s=%s\n", v.name, v.value)
+		}
+	}
+
+	_, _ = fmt.Fprintf(&sb, "remove it with: docker rm -f %s", id)
+
+	return sb.String()
+}
+
+// RemoveKeptContainers removes all the containers that are kept running by WithKeepOnFailure, including their
+// anonymous volumes. It returns the ids of the removed containers.
+func RemoveKeptContainers(ctx context.Context) ([]string, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	defer cli.Close() // nolint: errcheck
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", KeepOnFailureLabel+"=true")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list kept containers: %w", err)
+	}
+
+	removed := make([]string, 0, len(containers))
+	errs := make(errorCollection, 0)
+
+	for _, c := range containers {
+		if err := cli.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+			errs.Append(&ContainerError{ID: c.ID, Phase: PhaseTerminate, Err: err})
+
+			continue
+		}
+
+		removed = append(removed, c.ID)
+	}
+
+	return removed, errs.AsError()
+}
@@ -0,0 +1,110 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+func TestStartGenericContainer_KeepOnFailure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		mockContainer mock.ContainerMocker
+		failed        bool
+		expectedLogs  []string
+	}{
+		{
+			scenario: "test passes",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("Terminate", testifymock.Anything).
+					Return(nil).Once()
+			}),
+		},
+		{
+			scenario: "test fails",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("GetContainerID").
+					Return("42")
+
+				c.On("Ports", testifymock.Anything).
+					Return(nat.PortMap{
+						"5432/tcp": {{HostIP: "0.0.0.0", HostPort: "32768"}},
+						"8080/tcp": {{HostIP: "0.0.0.0", HostPort: "32769"}},
+					}, nil).Once()
+
+				c.On("Host", testifymock.Anything).
+					Return("localhost", nil).Once()
+			}),
+			failed: true,
+			expectedLogs: []string{`container "postgres" (42) is kept running because the test failed
+  docker logs -f 42
+  docker exec -it 42 sh
+endpoints:
+  POSTGRES_5432_HOST=localhost
+  POSTGRES_5432_PORT=32768
+  POSTGRES_8080_HOST=localhost
+  POSTGRES_8080_PORT=32769
+remove it with: docker rm -f 42`},
+		},
+		{
+			scenario: "no endpoints",
+			mockContainer: mock.MockContainer(func(c *mock.Container) {
+				c.On("GetContainerID").
+					Return("42")
+
+				c.On("Ports", testifymock.Anything).
+					Return(nil, errors.New("ports error")).Once()
+			}),
+			failed: true,
+			expectedLogs: []string{`container "postgres" (42) is kept running because the test failed
+  docker logs -f 42
+  docker exec -it 42 sh
+endpoints: could not get container "postgres" ports: ports error
+remove it with: docker rm -f 42`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			tb := &fakeTB{failed: tc.failed}
+			mc := tc.mockContainer(t)
+
+			var req testcontainers.GenericContainerRequest
+
+			c, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres"},
+				WithKeepOnFailure(tb),
+				fakeGenericContainer(func(_ context.Context, r testcontainers.GenericContainerRequest) (Container, error) {
+					req = r
+
+					return mc, nil
+				}),
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, "true", req.Labels[KeepOnFailureLabel])
+
+			cfg := &container.Config{Labels: map[string]string{reaperSessionLabel: "session", reaperReapLabel: "true"}}
+
+			req.ConfigModifier(cfg)
+
+			assert.Empty(t, cfg.Labels)
+
+			require.NoError(t, c.Terminate(context.Background()))
+
+			assert.Equal(t, tc.expectedLogs, tb.logs)
+		})
+	}
+}
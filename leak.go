@@ -0,0 +1,276 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const maxLeakStackDepth = 32
+
+// ownPackage is the import path of this package, its frames are skipped in the stack of the leaks.
+var ownPackage = callerPackage(0)
+
+// leaks tracks the containers that are started by this package until they are terminated.
+var leaks = &leakRegistry{
+	containers: make(map[string]trackedContainer),
+}
+
+// Leak is a container that is started by this package but never terminated.
+type Leak struct {
+	ID   string
+	Name string
+	// Test is the name of the test that starts the container, if any.
+	Test string
+	// Stack is the stack trace of the call that starts the container.
+	Stack     string
+	StartedAt time.Time
+}
+
+// String describes the leak.
+func (l Leak) String() string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "container %q (%s) started at %s", l.Name, l.ID, l.StartedAt.Format(time.RFC3339))
+
+	if l.Test != "" {
+		_, _ = fmt.Fprintf(&sb, " by %s", l.Test)
+	}
+
+	if l.Stack != "" {
+		sb.WriteString(":\n")
+		sb.WriteString(l.Stack)
+	}
+
+	return sb.String()
+}
+
+// LeakError is the error when there are containers that are not terminated.
+type LeakError struct {
+	Leaks []Leak
+}
+
+// Error returns the error message.
+func (e *LeakError) Error() string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "%d container(s) are not terminated", len(e.Leaks))
+
+	for _, l := range e.Leaks {
+		sb.WriteString("\n\n")
+		sb.WriteString(l.String())
+	}
+
+	return sb.String()
+}
+
+// Leaks returns the containers that are started by this package but not terminated yet, in the order they are started.
+func Leaks() []Leak {
+	return leaks.list(func(Leak) bool { return true })
+}
+
+// CheckLeaks returns a LeakError if there are containers that are started by this package but not terminated yet. It
+// is meant to be called at the end of TestMain, after stopping the shared containers.
+func CheckLeaks() error {
+	return leakError(Leaks())
+}
+
+// CheckLeaksT fails the test if the containers started by the test or its subtests are not terminated when the test
+// completes. It must be called before starting the containers so that the check runs after their cleanups.
+//
+// The test of a container is known if it is started by StartGenericContainerT or StartGenericContainersT, otherwise it
+// is the top-level test found in the stack trace.
+func CheckLeaksT(tb testing.TB) {
+	tb.Helper()
+
+	name := tb.Name()
+
+	tb.Cleanup(func() {
+		err := leakError(leaks.list(func(l Leak) bool {
+			return l.Test == name || strings.HasPrefix(l.Test, name+"/")
+		}))
+		if err != nil {
+			tb.Errorf("%s", err.Error())
+		}
+	})
+}
+
+func leakError(leaks []Leak) error {
+	if len(leaks) == 0 {
+		return nil
+	}
+
+	return &LeakError{Leaks: leaks}
+}
+
+type leakRegistry struct {
+	mu         sync.Mutex
+	containers map[string]trackedContainer
+}
+
+// hooks tracks the container from its creation until its termination. The container is also tracked when it is
+// started, in case it is reused and not created.
+func (r *leakRegistry) hooks(name string, origin containerOrigin) testcontainers.ContainerLifecycleHooks {
+	track := func(_ context.Context, c testcontainers.Container) error {
+		r.track(c.GetContainerID(), name, origin)
+
+		return nil
+	}
+
+	return testcontainers.ContainerLifecycleHooks{
+		PostCreates: []testcontainers.ContainerHook{track},
+		PostStarts:  []testcontainers.ContainerHook{track},
+		PostTerminates: []testcontainers.ContainerHook{
+			func(_ context.Context, c testcontainers.Container) error {
+				r.untrack(c.GetContainerID())
+
+				return nil
+			},
+		},
+	}
+}
+
+func (r *leakRegistry) track(id, name string, origin containerOrigin) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.containers[id]; ok {
+		return
+	}
+
+	r.containers[id] = trackedContainer{
+		name:      name,
+		origin:    origin,
+		startedAt: time.Now(),
+	}
+}
+
+// untrack stops tracking the container, it is used for the containers that are intentionally not terminated.
+func (r *leakRegistry) untrack(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.containers, id)
+}
+
+func (r *leakRegistry) list(filter func(l Leak) bool) []Leak {
+	r.mu.Lock()
+
+	result := make([]Leak, 0, len(r.containers))
+
+	for id, c := range r.containers {
+		l := c.leak(id)
+
+		if filter(l) {
+			result = append(result, l)
+		}
+	}
+
+	r.mu.Unlock()
+
+	slices.SortFunc(result, func(a, b Leak) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return result
+}
+
+// containerOrigin is where a container is started.
+type containerOrigin struct {
+	test  string
+	stack []runtime.Frame
+}
+
+// captureOrigin records the stack of the caller of the public function of this package that starts the container.
+// The test is found in the stack unless it is already known.
+//
+// The frames are resolved right away because a program counter could expand to several frames when the functions are
+// inlined, so the frames of this package could not be skipped by slicing the program counters.
+func captureOrigin(test string) containerOrigin {
+	pcs := make([]uintptr, maxLeakStackDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	stack := make([]runtime.Frame, 0, maxLeakStackDepth)
+
+	for {
+		f, more := frames.Next()
+
+		if len(stack) > 0 || !isOwnFrame(f) {
+			stack = append(stack, f)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if test == "" {
+		test = stackTestName(stack)
+	}
+
+	return containerOrigin{test: test, stack: stack}
+}
+
+func isOwnFrame(f runtime.Frame) bool {
+	return funcPackage(f.Function) == ownPackage && !strings.HasSuffix(f.File, "_test.go")
+}
+
+// stackTestName returns the name of the top-level test, which is the function called by testing.tRunner.
+func stackTestName(stack []runtime.Frame) string {
+	for i, f := range stack {
+		if f.Function != "testing.tRunner" || i == 0 {
+			continue
+		}
+
+		name := stack[i-1].Function
+		name = name[strings.LastIndex(name, "/")+1:]
+		name = name[strings.Index(name, ".")+1:]
+
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[:i]
+		}
+
+		return name
+	}
+
+	return ""
+}
+
+// formatStack formats the stack until the test runner or the start of the goroutine.
+func formatStack(stack []runtime.Frame) string {
+	var sb strings.Builder
+
+	for _, f := range stack {
+		if f.Function == "testing.tRunner" || f.Function == "runtime.goexit" || f.Function == "runtime.main" {
+			break
+		}
+
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// trackedContainer is a container that is tracked until it is terminated.
+type trackedContainer struct {
+	name      string
+	origin    containerOrigin
+	startedAt time.Time
+}
+
+func (c trackedContainer) leak(id string) Leak {
+	return Leak{
+		ID:        id,
+		Name:      c.name,
+		Test:      c.origin.test,
+		Stack:     formatStack(c.origin.stack),
+		StartedAt: c.startedAt,
+	}
+}
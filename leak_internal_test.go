@@ -0,0 +1,179 @@
+package testcontainers
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+// leakingContainer runs the lifecycle hooks of the request like testcontainers does.
+func leakingContainer(t *testing.T, id string) fakeGenericContainer {
+	t.Helper()
+
+	return func(ctx context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+		c := mock.MockContainer(func(c *mock.Container) {
+			c.On("GetContainerID").
+				Return(id)
+		})(t)
+
+		for _, h := range req.LifecycleHooks {
+			for _, f := range h.PostCreates {
+				require.NoError(t, f(ctx, c))
+			}
+
+			for _, f := range h.PostStarts {
+				require.NoError(t, f(ctx, c))
+			}
+		}
+
+		return c, nil
+	}
+}
+
+// terminate runs the post terminate hooks of the request like testcontainers does.
+func terminate(t *testing.T, hooks []testcontainers.ContainerLifecycleHooks, c Container) {
+	t.Helper()
+
+	for _, h := range hooks {
+		for _, f := range h.PostTerminates {
+			require.NoError(t, f(context.Background(), c))
+		}
+	}
+}
+
+func leaksByID(id string) []Leak {
+	return leaks.list(func(l Leak) bool {
+		return l.ID == id
+	})
+}
+
+func TestStartGenericContainer_Leak(t *testing.T) {
+	t.Parallel()
+
+	var hooks []testcontainers.ContainerLifecycleHooks
+
+	start := leakingContainer(t, "leak-1")
+
+	c, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres"},
+		fakeGenericContainer(func(ctx context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			hooks = req.LifecycleHooks
+
+			return start(ctx, req)
+		}),
+	)
+	require.NoError(t, err)
+
+	actual := leaksByID("leak-1")
+
+	require.Len(t, actual, 1)
+	assert.Equal(t, "postgres", actual[0].Name)
+	assert.Equal(t, "TestStartGenericContainer_Leak", actual[0].Test)
+	assert.True(t, strings.HasPrefix(actual[0].Stack, "go.nhat.io/testcontainers-extra.TestStartGenericContainer_Leak\n\t"), actual[0].Stack)
+	assert.Contains(t, actual[0].Stack, "leak_internal_test.go:")
+	assert.WithinDuration(t, time.Now(), actual[0].StartedAt, time.Minute)
+
+	terminate(t, hooks, c)
+
+	assert.Empty(t, leaksByID("leak-1"))
+}
+
+func TestStartGenericContainersT_Leak(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{name: "TestLeaks/batch"}
+	checkTB := &fakeTB{name: "TestLeaks"}
+
+	checkTB.run(func() {
+		CheckLeaksT(checkTB)
+
+		tb.run(func() {
+			StartGenericContainersT(tb, []StartGenericContainerRequest{
+				{Request: ContainerRequest{Name: "postgres"}, Options: []GenericContainerOption{leakingContainer(t, "leak-2")}},
+			})
+
+			// The container is not terminated by the test.
+			tb.cleanups = nil
+		})
+	})
+
+	actual := leaksByID("leak-2")
+
+	require.Len(t, actual, 1)
+	assert.Equal(t, "TestLeaks/batch", actual[0].Test)
+	assert.Contains(t, actual[0].Stack, "leak_internal_test.go:")
+
+	require.Len(t, checkTB.errors, 1)
+	assert.True(t, strings.HasPrefix(checkTB.errors[0], "1 container(s) are not terminated\n\ncontainer \"postgres\" (leak-2) started at "), checkTB.errors[0])
+}
+
+func TestLeak_String(t *testing.T) {
+	t.Parallel()
+
+	l := Leak{
+		ID:        "42",
+		Name:      "postgres",
+		Test:      "TestRepository",
+		Stack:     "repository.TestRepository\n\t/app/repository_test.go:10",
+		StartedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	expected := `container "postgres" (42) started at 2020-01-02T03:04:05Z by TestRepository:
+repository.TestRepository
+	/app/repository_test.go:10`
+
+	assert.Equal(t, expected, l.String())
+}
+
+func TestFormatStack(t *testing.T) {
+	t.Parallel()
+
+	stack := []runtime.Frame{
+		{Function: "example.com/repository.newDatabase", File: "/app/repository_test.go", Line: 20},
+		{Function: "example.com/repository.TestRepository", File: "/app/repository_test.go", Line: 10},
+		{Function: "testing.tRunner", File: "/go/src/testing/testing.go", Line: 1792},
+		{Function: "runtime.goexit", File: "/go/src/runtime/asm_amd64.s", Line: 1700},
+	}
+
+	expected := `example.com/repository.newDatabase
+	/app/repository_test.go:20
+example.com/repository.TestRepository
+	/app/repository_test.go:10`
+
+	assert.Equal(t, expected, formatStack(stack))
+	assert.Equal(t, "TestRepository", stackTestName(stack))
+	assert.Empty(t, stackTestName(stack[2:]))
+}
+
+func TestStackTestName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("subtest", func(t *testing.T) {
+		t.Parallel()
+
+		origin := captureOrigin("")
+
+		assert.Equal(t, "TestStackTestName", origin.test)
+
+		f, ok := origin.caller()
+
+		require.True(t, ok)
+		assert.Equal(t, "go.nhat.io/testcontainers-extra.TestStackTestName.func1", f.Function)
+		assert.True(t, strings.HasSuffix(f.File, "leak_internal_test.go"))
+	})
+
+	done := make(chan containerOrigin)
+
+	go func() {
+		done <- captureOrigin("")
+	}()
+
+	assert.Empty(t, (<-done).test, "the test is unknown in another goroutine")
+}
@@ -37,7 +37,7 @@ func WithImageTag(tag string) GenericContainerOption {
 // WithCallback adds a new callback to run after the container is ready.
 func WithCallback(f ContainerCallback) GenericContainerOption {
 	return genericContainerOptionFunc(func(o *genericContainerOptions) {
-		o.callbacks = append(o.callbacks, f)
+		o.callbacks = append(o.callbacks, containerCallback{f: f})
 	})
 }
 
@@ -47,3 +47,52 @@ func WithProviderType(providerType testcontainers.ProviderType) GenericContainer
 		o.providerType = providerType
 	})
 }
+
+// WithRollback terminates all the started containers and cancels the starts in progress if any of the requests fails.
+// The containers are not returned in that case. Rollback implies WithFailFast.
+func WithRollback() BatchOption {
+	return batchOptionFunc(func(o *batchOptions) {
+		o.rollback = true
+		o.failFast = true
+	})
+}
+
+// WithFailFast cancels the starts in progress, including their wait strategies, as soon as a request fails. The
+// requests that are not started yet are skipped. The error is a FailFastError.
+func WithFailFast() BatchOption {
+	return batchOptionFunc(func(o *batchOptions) {
+		o.failFast = true
+	})
+}
+
+// WithMaxConcurrency limits the number of containers that are started or stopped at the same time. There is no limit
+// if n is not positive.
+func WithMaxConcurrency(n int) MaxConcurrency {
+	return MaxConcurrency(n)
+}
+
+// MaxConcurrency is the maximum number of containers that are started or stopped at the same time.
+type MaxConcurrency int
+
+func (n MaxConcurrency) applyBatchOptions(o *batchOptions) {
+	o.maxConcurrency = int(n)
+}
+
+func (n MaxConcurrency) applyStopOptions(o *stopOptions) {
+	o.maxConcurrency = int(n)
+}
+
+// WithTerminateOptions sets the options for terminating the containers, such as stop timeout and volume removal.
+func WithTerminateOptions(opts ...TerminateOption) StopOption {
+	return stopOptionFunc(func(o *stopOptions) {
+		o.terminateOptions = append(o.terminateOptions, opts...)
+	})
+}
+
+// WithReverseOrder stops the containers one by one in the reverse order of the given containers. When the containers
+// are given in the startup order, the ones that are started last are stopped first.
+func WithReverseOrder() StopOption {
+	return stopOptionFunc(func(o *stopOptions) {
+		o.reverseOrder = true
+	})
+}
@@ -0,0 +1,288 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// The labels that testcontainers adds to the resources it creates.
+	testcontainersLabel       = "org.testcontainers"
+	testcontainersReaperLabel = "org.testcontainers.reaper"
+)
+
+// The kinds of the orphans.
+const (
+	OrphanContainer OrphanKind = "container"
+	OrphanNetwork   OrphanKind = "network"
+	OrphanVolume    OrphanKind = "volume"
+)
+
+// OrphanKind is the kind of Docker resource of an orphan.
+type OrphanKind string
+
+// Orphan is a Docker resource created by this package that is left behind, for example, when a CI job is killed and
+// the reaper is disabled.
+type Orphan struct {
+	Kind      OrphanKind
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	Labels    map[string]string
+}
+
+// String describes the orphan.
+func (o Orphan) String() string {
+	return fmt.Sprintf("%s %s (%s) created at %s", o.Kind, o.Name, o.ID, o.CreatedAt.Format(time.RFC3339))
+}
+
+// OrphanOption is option for finding the orphans.
+type OrphanOption func(o *orphanOptions)
+
+type orphanOptions struct {
+	olderThan time.Duration
+	runID     string
+	pkg       string
+	labels    map[string]string
+	dryRun    bool
+	all       bool
+
+	now    func() time.Time
+	client func(ctx context.Context) (orphanClient, error)
+}
+
+type orphanClient interface {
+	ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
+	ContainerRemove(ctx context.Context, id string, options container.RemoveOptions) error
+	NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	NetworkRemove(ctx context.Context, id string) error
+	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	VolumeRemove(ctx context.Context, id string, force bool) error
+	Close() error
+}
+
+// WithOrphanOlderThan only finds the orphans that are created before the given duration.
+func WithOrphanOlderThan(d time.Duration) OrphanOption {
+	return func(o *orphanOptions) {
+		o.olderThan = d
+	}
+}
+
+// WithOrphanRunID only finds the orphans of a test run, see RunID. The run id is matched against the provenance label
+// and the testcontainers session label.
+func WithOrphanRunID(id string) OrphanOption {
+	return func(o *orphanOptions) {
+		o.runID = id
+	}
+}
+
+// WithOrphanPackage only finds the orphans started by the tests of a package. Only the containers that are started with
+// WithProvenanceLabels have the label of the package.
+func WithOrphanPackage(pkg string) OrphanOption {
+	return func(o *orphanOptions) {
+		o.pkg = pkg
+	}
+}
+
+// WithOrphanLabel only finds the orphans that have the label. Any value matches if the value is empty.
+func WithOrphanLabel(key, value string) OrphanOption {
+	return func(o *orphanOptions) {
+		o.labels[key] = value
+	}
+}
+
+// WithOrphanAllTestcontainers also finds the resources that are not created by this package but by any testcontainers
+// client, including the networks and the volumes. Be careful, the resources of the test runs that are still running
+// match too, unless they are filtered out by the other options.
+func WithOrphanAllTestcontainers() OrphanOption {
+	return func(o *orphanOptions) {
+		o.all = true
+	}
+}
+
+// WithOrphanDryRun does not remove the orphans, RemoveOrphans only returns what would be removed.
+func WithOrphanDryRun() OrphanOption {
+	return func(o *orphanOptions) {
+		o.dryRun = true
+	}
+}
+
+func newOrphanOptions(opts []OrphanOption) orphanOptions {
+	o := orphanOptions{
+		labels: make(map[string]string),
+		now:    time.Now,
+		client: func(ctx context.Context) (orphanClient, error) {
+			return testcontainers.NewDockerClientWithOpts(ctx)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// FindOrphans finds the resources that are created by this package and match the options. This package only labels
+// the containers, see ManagedLabel, so the networks and the volumes are only found with WithOrphanAllTestcontainers.
+// The reaper, the persistent containers and the containers that are kept on failure are not orphans, the latter are
+// removed by RemoveKeptContainers.
+func FindOrphans(ctx context.Context, opts ...OrphanOption) ([]Orphan, error) {
+	o := newOrphanOptions(opts)
+
+	cli, err := o.client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	defer cli.Close() // nolint: errcheck
+
+	return findOrphans(ctx, cli, o)
+}
+
+// RemoveOrphans removes the orphans that match the options, see FindOrphans. The containers are removed first,
+// including their anonymous volumes, then the networks and the volumes. It returns the removed orphans, or the orphans
+// that would be removed in the dry-run mode.
+func RemoveOrphans(ctx context.Context, opts ...OrphanOption) ([]Orphan, error) {
+	o := newOrphanOptions(opts)
+
+	cli, err := o.client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	defer cli.Close() // nolint: errcheck
+
+	orphans, err := findOrphans(ctx, cli, o)
+	if err != nil || o.dryRun {
+		return orphans, err
+	}
+
+	removed := make([]Orphan, 0, len(orphans))
+	errs := make(errorCollection, 0)
+
+	for _, orphan := range orphans {
+		if err := removeOrphan(ctx, cli, orphan); err != nil {
+			errs.Append(fmt.Errorf("could not remove %s: %w", orphan, err))
+
+			continue
+		}
+
+		removed = append(removed, orphan)
+	}
+
+	return removed, errs.AsError()
+}
+
+func removeOrphan(ctx context.Context, cli orphanClient, o Orphan) error {
+	switch o.Kind {
+	case OrphanContainer:
+		return cli.ContainerRemove(ctx, o.ID, container.RemoveOptions{Force: true, RemoveVolumes: true})
+
+	case OrphanNetwork:
+		return cli.NetworkRemove(ctx, o.ID)
+
+	case OrphanVolume:
+		return cli.VolumeRemove(ctx, o.ID, true)
+	}
+
+	return nil
+}
+
+func findOrphans(ctx context.Context, cli orphanClient, o orphanOptions) ([]Orphan, error) {
+	args := filters.NewArgs(filters.Arg("label", testcontainersLabel+"=true"))
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("could not list containers: %w", err)
+	}
+
+	networks, err := cli.NetworkList(ctx, network.ListOptions{Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("could not list networks: %w", err)
+	}
+
+	volumes, err := cli.VolumeList(ctx, volume.ListOptions{Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes: %w", err)
+	}
+
+	orphans := make([]Orphan, 0, len(containers)+len(networks)+len(volumes.Volumes))
+
+	for _, c := range containers {
+		if c.Labels[testcontainersReaperLabel] == "true" || c.Labels[PersistentLabel] == "true" || c.Labels[KeepOnFailureLabel] == "true" {
+			continue
+		}
+
+		name := c.ID
+
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		orphans = append(orphans, Orphan{Kind: OrphanContainer, ID: c.ID, Name: name, CreatedAt: time.Unix(c.Created, 0), Labels: c.Labels})
+	}
+
+	for _, n := range networks {
+		orphans = append(orphans, Orphan{Kind: OrphanNetwork, ID: n.ID, Name: n.Name, CreatedAt: n.Created, Labels: n.Labels})
+	}
+
+	for _, v := range volumes.Volumes {
+		createdAt, _ := time.Parse(time.RFC3339, v.CreatedAt) // nolint: errcheck
+
+		orphans = append(orphans, Orphan{Kind: OrphanVolume, ID: v.Name, Name: v.Name, CreatedAt: createdAt, Labels: v.Labels})
+	}
+
+	return slices.DeleteFunc(orphans, func(orphan Orphan) bool {
+		return !o.match(orphan)
+	}), nil
+}
+
+func (o orphanOptions) match(orphan Orphan) bool {
+	if !o.all && !isManaged(orphan.Labels) {
+		return false
+	}
+
+	if o.olderThan > 0 && (orphan.CreatedAt.IsZero() || orphan.CreatedAt.After(o.now().Add(-o.olderThan))) {
+		return false
+	}
+
+	if o.runID != "" && orphan.Labels[RunIDLabel] != o.runID && orphan.Labels[reaperSessionLabel] != o.runID {
+		return false
+	}
+
+	if o.pkg != "" && orphan.Labels[PackageLabel] != o.pkg {
+		return false
+	}
+
+	for k, v := range o.labels {
+		actual, ok := orphan.Labels[k]
+
+		if !ok || (v != "" && actual != v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isManaged tells whether the resource is created by this package. The containers of the earlier versions only have the
+// fingerprint label.
+func isManaged(labels map[string]string) bool {
+	if labels[ManagedLabel] == "true" {
+		return true
+	}
+
+	_, ok := labels[FingerprintLabel]
+
+	return ok
+}
@@ -0,0 +1,263 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOrphanClient lists the resources and records the removed ones.
+type fakeOrphanClient struct {
+	containers []container.Summary
+	networks   []network.Summary
+	volumes    []*volume.Volume
+	listErr    error
+	removeErr  error
+	removed    []string
+}
+
+func (c *fakeOrphanClient) ContainerList(context.Context, container.ListOptions) ([]container.Summary, error) {
+	return c.containers, c.listErr
+}
+
+func (c *fakeOrphanClient) ContainerRemove(_ context.Context, id string, _ container.RemoveOptions) error {
+	return c.remove("container " + id)
+}
+
+func (c *fakeOrphanClient) NetworkList(context.Context, network.ListOptions) ([]network.Summary, error) {
+	return c.networks, nil
+}
+
+func (c *fakeOrphanClient) NetworkRemove(_ context.Context, id string) error {
+	return c.remove("network " + id)
+}
+
+func (c *fakeOrphanClient) VolumeList(context.Context, volume.ListOptions) (volume.ListResponse, error) {
+	return volume.ListResponse{Volumes: c.volumes}, nil
+}
+
+func (c *fakeOrphanClient) VolumeRemove(_ context.Context, id string, _ bool) error {
+	return c.remove("volume " + id)
+}
+
+func (c *fakeOrphanClient) Close() error {
+	return nil
+}
+
+func (c *fakeOrphanClient) remove(s string) error {
+	if c.removeErr != nil {
+		return c.removeErr
+	}
+
+	c.removed = append(c.removed, s)
+
+	return nil
+}
+
+func (c *fakeOrphanClient) option(o *orphanOptions) {
+	o.now = func() time.Time {
+		return time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	}
+
+	o.client = func(context.Context) (orphanClient, error) {
+		return c, nil
+	}
+}
+
+func newFakeOrphanClient() *fakeOrphanClient {
+	return &fakeOrphanClient{
+		containers: []container.Summary{
+			{
+				ID:      "c1",
+				Names:   []string{"/postgres"},
+				Created: time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC).Unix(),
+				Labels: map[string]string{
+					testcontainersLabel: "true",
+					reaperSessionLabel:  "session-1",
+					ManagedLabel:        "true",
+					RunIDLabel:          "run-1",
+					PackageLabel:        "example.com/repository",
+					"team":              "a",
+				},
+			},
+			{
+				ID:      "c2",
+				Names:   []string{"/kafka"},
+				Created: time.Date(2020, 1, 2, 11, 30, 0, 0, time.UTC).Unix(),
+				Labels:  map[string]string{testcontainersLabel: "true", reaperSessionLabel: "session-2"},
+			},
+			{
+				ID:      "c3",
+				Names:   []string{"/redis"},
+				Created: time.Date(2020, 1, 2, 8, 0, 0, 0, time.UTC).Unix(),
+				Labels:  map[string]string{testcontainersLabel: "true", reaperSessionLabel: "session-3", FingerprintLabel: "fp"},
+			},
+			{
+				ID:     "reaper",
+				Labels: map[string]string{testcontainersLabel: "true", testcontainersReaperLabel: "true"},
+			},
+			{
+				ID:     "persistent",
+				Labels: map[string]string{testcontainersLabel: "true", ManagedLabel: "true", PersistentLabel: "true"},
+			},
+			{
+				ID:     "kept",
+				Labels: map[string]string{testcontainersLabel: "true", ManagedLabel: "true", KeepOnFailureLabel: "true"},
+			},
+		},
+		networks: []network.Summary{
+			{
+				ID:      "n1",
+				Name:    "network",
+				Created: time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC),
+				Labels:  map[string]string{testcontainersLabel: "true", reaperSessionLabel: "session-1"},
+			},
+		},
+		volumes: []*volume.Volume{
+			{
+				Name:      "v1",
+				CreatedAt: "2020-01-02T11:00:00Z",
+				Labels:    map[string]string{testcontainersLabel: "true", reaperSessionLabel: "session-2"},
+			},
+		},
+	}
+}
+
+func orphanIDs(orphans []Orphan) []string {
+	ids := make([]string, 0, len(orphans))
+
+	for _, o := range orphans {
+		ids = append(ids, string(o.Kind)+" "+o.ID)
+	}
+
+	return ids
+}
+
+func TestFindOrphans(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		opts     []OrphanOption
+		expected []string
+	}{
+		{
+			scenario: "this package",
+			expected: []string{"container c1", "container c3"},
+		},
+		{
+			scenario: "all testcontainers",
+			opts:     []OrphanOption{WithOrphanAllTestcontainers()},
+			expected: []string{"container c1", "container c2", "container c3", "network n1", "volume v1"},
+		},
+		{
+			scenario: "older than",
+			opts:     []OrphanOption{WithOrphanOlderThan(2 * time.Hour)},
+			expected: []string{"container c1", "container c3"},
+		},
+		{
+			scenario: "older than with all testcontainers",
+			opts:     []OrphanOption{WithOrphanOlderThan(time.Hour), WithOrphanAllTestcontainers()},
+			expected: []string{"container c1", "container c3", "network n1", "volume v1"},
+		},
+		{
+			scenario: "run id label",
+			opts:     []OrphanOption{WithOrphanRunID("run-1")},
+			expected: []string{"container c1"},
+		},
+		{
+			scenario: "session id",
+			opts:     []OrphanOption{WithOrphanRunID("session-2")},
+			expected: []string{},
+		},
+		{
+			scenario: "session id with all testcontainers",
+			opts:     []OrphanOption{WithOrphanRunID("session-2"), WithOrphanAllTestcontainers()},
+			expected: []string{"container c2", "volume v1"},
+		},
+		{
+			scenario: "package",
+			opts:     []OrphanOption{WithOrphanPackage("example.com/repository")},
+			expected: []string{"container c1"},
+		},
+		{
+			scenario: "label with value",
+			opts:     []OrphanOption{WithOrphanLabel("team", "b")},
+			expected: []string{},
+		},
+		{
+			scenario: "label without value",
+			opts:     []OrphanOption{WithOrphanLabel("team", "")},
+			expected: []string{"container c1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			cli := newFakeOrphanClient()
+
+			actual, err := FindOrphans(context.Background(), append(tc.opts, cli.option)...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, orphanIDs(actual))
+		})
+	}
+}
+
+func TestFindOrphans_Error(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeOrphanClient()
+	cli.listErr = errors.New("list error")
+
+	actual, err := FindOrphans(context.Background(), cli.option)
+
+	assert.Nil(t, actual)
+	require.EqualError(t, err, "could not list containers: list error")
+}
+
+func TestRemoveOrphans(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeOrphanClient()
+
+	actual, err := RemoveOrphans(context.Background(), WithOrphanRunID("session-1"), WithOrphanAllTestcontainers(), cli.option)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"container c1", "network n1"}, orphanIDs(actual))
+	assert.Equal(t, []string{"container c1", "network n1"}, cli.removed)
+	assert.Equal(t, "container postgres (c1) created at 2020-01-02T09:00:00Z", actual[0].String())
+}
+
+func TestRemoveOrphans_DryRun(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeOrphanClient()
+
+	actual, err := RemoveOrphans(context.Background(), WithOrphanDryRun(), cli.option)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"container c1", "container c3"}, orphanIDs(actual))
+	assert.Empty(t, cli.removed)
+}
+
+func TestRemoveOrphans_Error(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeOrphanClient()
+	cli.removeErr = errors.New("remove error")
+
+	actual, err := RemoveOrphans(context.Background(), WithOrphanPackage("example.com/repository"), cli.option)
+
+	assert.Empty(t, actual)
+	require.EqualError(t, err, "could not remove container postgres (c1) created at 2020-01-02T09:00:00Z: remove error")
+}
@@ -0,0 +1,151 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	// PersistEnv is the environment variable that keeps all the containers running between the test runs when it is
+	// true.
+	PersistEnv = "TESTCONTAINERS_EXTRA_PERSIST"
+	// PersistentLabel is the label of the containers that are kept running between the test runs.
+	PersistentLabel = "io.nhat.testcontainers-extra.persistent"
+
+	// The labels that the reaper uses to find the containers of a test session.
+	reaperSessionLabel = "org.testcontainers.sessionId"
+	reaperReapLabel    = "org.testcontainers.reap"
+)
+
+type persistentOptions struct {
+	enabled bool
+
+	lookup func(ctx context.Context, name string) (*container.Summary, error)
+	remove func(ctx context.Context, id string) error
+}
+
+func newPersistentOptions() persistentOptions {
+	enabled, _ := strconv.ParseBool(os.Getenv(PersistEnv)) // nolint: errcheck
+
+	return persistentOptions{
+		enabled: enabled,
+		lookup:  lookupContainer,
+		remove:  removeContainer,
+	}
+}
+
+// WithPersistentContainer keeps the container running after the tests, which is useful for local development. The
+// container is also persistent when the TESTCONTAINERS_EXTRA_PERSIST environment variable is true.
+//
+// The next run reattaches to the container with the same name if its fingerprint matches the request, otherwise the
+// container is recreated. The container is named after the fingerprint if the request has no name. Terminating a
+// persistent container does nothing, and the reaper does not remove it either.
+func WithPersistentContainer() GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.persistent.enabled = true
+	})
+}
+
+// persistContainer prepares the request for reattaching to the persistent container. The existing container is removed
+// if it is started with another request.
+func persistContainer(ctx context.Context, r *testcontainers.GenericContainerRequest, o persistentOptions, fp string) error {
+	if r.Name == "" {
+		r.Name = fingerprintContainerName(fp)
+	}
+
+	existing, err := o.lookup(ctx, r.Name)
+	if err != nil {
+		return fmt.Errorf("could not find persistent container %q: %w", r.Name, err)
+	}
+
+	if existing != nil && (existing.Labels[FingerprintLabel] != fp || existing.Labels[PersistentLabel] != "true") {
+		if err := o.remove(ctx, existing.ID); err != nil {
+			return fmt.Errorf("could not remove outdated persistent container %q: %w", r.Name, err)
+		}
+	}
+
+	r.Reuse = true
+	r.Labels[PersistentLabel] = "true"
+	r.ConfigModifier = withoutReaperLabels(r.ContainerRequest)
+
+	return nil
+}
+
+// withoutReaperLabels removes the labels of the test session so that the reaper does not remove the container.
+func withoutReaperLabels(r ContainerRequest) func(cfg *container.Config) {
+	return chainConfigModifier(r, func(cfg *container.Config) {
+		delete(cfg.Labels, reaperSessionLabel)
+		delete(cfg.Labels, reaperReapLabel)
+	})
+}
+
+// chainConfigModifier runs the config modifier of the request, or the default one of testcontainers if there is none,
+// then the given modifier.
+func chainConfigModifier(r ContainerRequest, next func(cfg *container.Config)) func(cfg *container.Config) {
+	modify := r.ConfigModifier
+
+	return func(cfg *container.Config) {
+		if modify != nil {
+			modify(cfg)
+		} else {
+			// Same as the default modifier of testcontainers.
+			cfg.Hostname = r.Hostname     // nolint: staticcheck
+			cfg.WorkingDir = r.WorkingDir // nolint: staticcheck
+			cfg.User = r.User             // nolint: staticcheck
+		}
+
+		next(cfg)
+	}
+}
+
+// persistentContainer is a container that is kept running after the tests.
+type persistentContainer struct {
+	Container
+}
+
+// Terminate does nothing, the container is kept running.
+func (c *persistentContainer) Terminate(context.Context, ...testcontainers.TerminateOption) error {
+	leaks.untrack(c.GetContainerID())
+
+	return nil
+}
+
+func lookupContainer(ctx context.Context, name string) (*container.Summary, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cli.Close() // nolint: errcheck
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", fmt.Sprintf("^/%s$", name))),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, nil
+	}
+
+	return &containers[0], nil
+}
+
+func removeContainer(ctx context.Context, id string) error {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer cli.Close() // nolint: errcheck
+
+	return cli.ContainerRemove(ctx, id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+}
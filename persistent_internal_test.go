@@ -0,0 +1,205 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+// fakePersistence replaces the docker calls of the persistent containers.
+type fakePersistence struct {
+	existing  *container.Summary
+	lookupErr error
+	removeErr error
+	removed   []string
+}
+
+func (f *fakePersistence) applyOptions(o *genericContainerOptions) {
+	o.persistent.lookup = func(context.Context, string) (*container.Summary, error) {
+		return f.existing, f.lookupErr
+	}
+
+	o.persistent.remove = func(_ context.Context, id string) error {
+		f.removed = append(f.removed, id)
+
+		return f.removeErr
+	}
+}
+
+func TestStartGenericContainer_Persistent(t *testing.T) {
+	t.Parallel()
+
+	request := ContainerRequest{Image: "postgres:16", User: "postgres"}
+	fp := mustFingerprint(t, request)
+
+	testCases := []struct {
+		scenario        string
+		existing        *container.Summary
+		expectedRemoved []string
+	}{
+		{
+			scenario: "new container",
+		},
+		{
+			scenario: "same fingerprint",
+			existing: &container.Summary{ID: "42", Labels: map[string]string{FingerprintLabel: fp, PersistentLabel: "true"}},
+		},
+		{
+			scenario:        "outdated fingerprint",
+			existing:        &container.Summary{ID: "42", Labels: map[string]string{FingerprintLabel: "outdated", PersistentLabel: "true"}},
+			expectedRemoved: []string{"42"},
+		},
+		{
+			scenario:        "not persistent",
+			existing:        &container.Summary{ID: "42", Labels: map[string]string{FingerprintLabel: fp}},
+			expectedRemoved: []string{"42"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p := &fakePersistence{existing: tc.existing}
+
+			var req testcontainers.GenericContainerRequest
+
+			c, err := StartGenericContainer(context.Background(), request,
+				WithPersistentContainer(),
+				p,
+				fakeGenericContainer(func(_ context.Context, r testcontainers.GenericContainerRequest) (Container, error) {
+					req = r
+
+					return mock.MockContainer(func(c *mock.Container) {
+						c.On("GetContainerID").
+							Return("42").Once()
+					})(t), nil
+				}),
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedRemoved, p.removed)
+			assert.True(t, req.Reuse)
+			assert.Equal(t, fingerprintContainerName(fp), req.Name)
+			assert.Equal(t, "true", req.Labels[PersistentLabel])
+
+			cfg := &container.Config{Labels: map[string]string{
+				reaperSessionLabel: "session",
+				reaperReapLabel:    "true",
+				FingerprintLabel:   fp,
+			}}
+
+			req.ConfigModifier(cfg)
+
+			assert.Equal(t, map[string]string{FingerprintLabel: fp}, cfg.Labels)
+			assert.Equal(t, "postgres", cfg.User)
+
+			// The container is kept running.
+			require.NoError(t, c.Terminate(context.Background()))
+		})
+	}
+}
+
+func TestStartGenericContainer_PersistentConfigModifier(t *testing.T) {
+	t.Parallel()
+
+	var req testcontainers.GenericContainerRequest
+
+	_, err := StartGenericContainer(context.Background(),
+		ContainerRequest{
+			Name:  "postgres",
+			Image: "postgres:16",
+			ConfigModifier: func(cfg *container.Config) {
+				cfg.Hostname = "db"
+			},
+		},
+		WithPersistentContainer(),
+		&fakePersistence{},
+		fakeGenericContainer(func(_ context.Context, r testcontainers.GenericContainerRequest) (Container, error) {
+			req = r
+
+			return nil, errors.New("start error")
+		}),
+	)
+	require.EqualError(t, err, "start error")
+
+	cfg := &container.Config{Labels: map[string]string{reaperSessionLabel: "session"}}
+
+	req.ConfigModifier(cfg)
+
+	assert.Equal(t, "postgres", req.Name)
+	assert.Equal(t, "db", cfg.Hostname)
+	assert.Empty(t, cfg.Labels)
+}
+
+func TestStartGenericContainer_PersistentError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		persistence   *fakePersistence
+		expectedError string
+	}{
+		{
+			scenario:      "lookup error",
+			persistence:   &fakePersistence{lookupErr: errors.New("lookup error")},
+			expectedError: `could not find persistent container "postgres": lookup error`,
+		},
+		{
+			scenario: "remove error",
+			persistence: &fakePersistence{
+				existing:  &container.Summary{ID: "42"},
+				removeErr: errors.New("remove error"),
+			},
+			expectedError: `could not remove outdated persistent container "postgres": remove error`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres"},
+				WithPersistentContainer(),
+				tc.persistence,
+				fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+					t.Fatal("the container must not be started")
+
+					return nil, nil
+				}),
+			)
+
+			assert.Nil(t, c)
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestStartGenericContainer_PersistentEnv(t *testing.T) {
+	t.Setenv(PersistEnv, "true")
+
+	var reuse bool
+
+	c, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres"},
+		&fakePersistence{},
+		fakeGenericContainer(func(_ context.Context, r testcontainers.GenericContainerRequest) (Container, error) {
+			reuse = r.Reuse
+
+			return mock.MockContainer(func(c *mock.Container) {
+				c.On("GetContainerID").
+					Return("42").Once()
+			})(t), nil
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.True(t, reuse)
+	require.NoError(t, c.Terminate(context.Background()))
+}
@@ -0,0 +1,119 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// The labels of the provenance of the containers.
+const (
+	// TestLabel is the label of the name of the test that starts the container.
+	TestLabel = "io.nhat.testcontainers-extra.test"
+	// PackageLabel is the label of the import path of the package that starts the container. The external test package
+	// of a package has the same import path.
+	PackageLabel = "io.nhat.testcontainers-extra.package"
+	// CallerLabel is the label of the file and the line that starts the container.
+	CallerLabel = "io.nhat.testcontainers-extra.caller"
+	// RunIDLabel is the label of the id of the test run, see RunID.
+	RunIDLabel = "io.nhat.testcontainers-extra.run-id"
+	// GitCommitLabel is the label of the git commit of the code that starts the container.
+	GitCommitLabel = "io.nhat.testcontainers-extra.git-commit"
+
+	// RunIDEnv is the environment variable that overrides the id of the test run.
+	RunIDEnv = "TESTCONTAINERS_EXTRA_RUN_ID"
+
+	gitCommitTimeout = 5 * time.Second
+)
+
+// gitCommitEnvs are the environment variables that contain the git commit in the CI environments.
+var gitCommitEnvs = []string{"GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT"}
+
+var gitCommit = sync.OnceValue(func() string {
+	return resolveGitCommit(os.Getenv)
+})
+
+// WithProvenanceLabels adds the labels of the provenance of the container so that the owner of the container is known:
+// the name of the test, the import path of the package, the file and the line that starts the container, the id of the
+// test run and the git commit. The labels without a value are not added.
+func WithProvenanceLabels() GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.provenance = true
+	})
+}
+
+// RunID returns the id of the test run, which is the same for all the packages tested by the same `go test` command.
+// It is the testcontainers session id unless the TESTCONTAINERS_EXTRA_RUN_ID environment variable is set.
+func RunID() string {
+	if id := os.Getenv(RunIDEnv); id != "" {
+		return id
+	}
+
+	return testcontainers.SessionID()
+}
+
+func addProvenanceLabels(labels map[string]string, origin containerOrigin) {
+	values := map[string]string{
+		TestLabel:      origin.test,
+		RunIDLabel:     RunID(),
+		GitCommitLabel: gitCommit(),
+	}
+
+	if f, ok := origin.caller(); ok {
+		values[PackageLabel] = framePackage(f)
+		values[CallerLabel] = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+
+	for k, v := range values {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+}
+
+// framePackage returns the import path of the package of the frame. The tests of an external test package, such as
+// example.com/foo_test, belong to the package that they test, example.com/foo.
+func framePackage(f runtime.Frame) string {
+	pkg := funcPackage(f.Function)
+
+	if strings.HasSuffix(f.File, "_test.go") {
+		pkg = strings.TrimSuffix(pkg, "_test")
+	}
+
+	return pkg
+}
+
+// caller returns the frame that calls this package.
+func (o containerOrigin) caller() (runtime.Frame, bool) {
+	if len(o.stack) == 0 {
+		return runtime.Frame{}, false
+	}
+
+	return o.stack[0], true
+}
+
+// resolveGitCommit returns the git commit from the environment variables of the CI environments, or from git.
+func resolveGitCommit(getenv func(string) string) string {
+	for _, env := range gitCommitEnvs {
+		if v := getenv(env); v != "" {
+			return v
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitCommitTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,138 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func startLabels(t *testing.T, opts ...GenericContainerOption) map[string]string {
+	t.Helper()
+
+	var labels map[string]string
+
+	opts = append(opts, fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+		labels = req.Labels
+
+		return nil, errors.New("start error")
+	}))
+
+	_, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres"}, opts...)
+	require.EqualError(t, err, "start error")
+
+	return labels
+}
+
+func TestStartGenericContainer_ProvenanceLabels(t *testing.T) {
+	t.Parallel()
+
+	labels := startLabels(t, WithProvenanceLabels())
+
+	assert.Equal(t, "TestStartGenericContainer_ProvenanceLabels", labels[TestLabel])
+	assert.Equal(t, "go.nhat.io/testcontainers-extra", labels[PackageLabel])
+	assert.True(t, strings.HasPrefix(filepath.Base(labels[CallerLabel]), "provenance_internal_test.go:"), labels[CallerLabel])
+	assert.Equal(t, RunID(), labels[RunIDLabel])
+	assert.Equal(t, gitCommit(), labels[GitCommitLabel])
+}
+
+func TestStartGenericContainer_NoProvenanceLabels(t *testing.T) {
+	t.Parallel()
+
+	labels := startLabels(t)
+
+	for _, l := range []string{TestLabel, PackageLabel, CallerLabel, RunIDLabel, GitCommitLabel} {
+		assert.NotContains(t, labels, l)
+	}
+}
+
+func TestStartGenericContainersT_ProvenanceLabels(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{name: "TestProvenance/batch"}
+
+	var labels map[string]string
+
+	tb.run(func() {
+		StartGenericContainersT(tb, []StartGenericContainerRequest{{
+			Request: ContainerRequest{Name: "postgres"},
+			Options: []GenericContainerOption{
+				WithProvenanceLabels(),
+				fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+					labels = req.Labels
+
+					return nil, errors.New("start error")
+				}),
+			},
+		}})
+	})
+
+	assert.Equal(t, "TestProvenance/batch", labels[TestLabel])
+	assert.True(t, strings.HasPrefix(filepath.Base(labels[CallerLabel]), "provenance_internal_test.go:"), labels[CallerLabel])
+}
+
+func TestFramePackage(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		frame    runtime.Frame
+		expected string
+	}{
+		{
+			scenario: "internal test",
+			frame:    runtime.Frame{Function: "example.com/foo.TestFoo", File: "/app/foo/foo_internal_test.go"},
+			expected: "example.com/foo",
+		},
+		{
+			scenario: "external test",
+			frame:    runtime.Frame{Function: "example.com/foo_test.TestFoo.func1", File: "/app/foo/foo_test.go"},
+			expected: "example.com/foo",
+		},
+		{
+			scenario: "helper of external test",
+			frame:    runtime.Frame{Function: "example.com/foo_test.newDatabase", File: "/app/foo/helper_test.go"},
+			expected: "example.com/foo",
+		},
+		{
+			scenario: "not a test",
+			frame:    runtime.Frame{Function: "example.com/foo_test.Start", File: "/app/foo_test/start.go"},
+			expected: "example.com/foo_test",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, framePackage(tc.frame))
+		})
+	}
+}
+
+func TestRunID(t *testing.T) {
+	assert.Equal(t, testcontainers.SessionID(), RunID())
+
+	t.Setenv(RunIDEnv, "42")
+
+	assert.Equal(t, "42", RunID())
+}
+
+func TestResolveGitCommit(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"CI_COMMIT_SHA": "gitlab",
+		"GIT_COMMIT":    "jenkins",
+	}
+
+	assert.Equal(t, "gitlab", resolveGitCommit(func(key string) string {
+		return env[key]
+	}))
+}
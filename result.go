@@ -0,0 +1,98 @@
+package testcontainers
+
+import (
+	"context"
+	"slices"
+)
+
+// StartedContainer is the result of a StartGenericContainerRequest.
+type StartedContainer struct {
+	// Name is the name of the request before applying the options.
+	Name string
+	// ContainerName is the name of the container after applying the options, such as WithNamePrefix and
+	// WithNameSuffix.
+	ContainerName string
+	// Container is the started container, it could be nil if the container could not be created.
+	Container Container
+	// Err is the error of the request, if any.
+	Err error
+
+	level int
+}
+
+// StartedContainers contains the results of StartGenericContainerBatch in the order of the requests.
+type StartedContainers []StartedContainer
+
+// Containers returns the started containers in the order of the requests, or nil if there is none.
+func (s StartedContainers) Containers() []Container {
+	var containers []Container
+
+	for _, r := range s {
+		if r.Container != nil {
+			containers = append(containers, r.Container)
+		}
+	}
+
+	return containers
+}
+
+// Lookup finds the result by the name of the request, or by the name of the container if no request has that name. If
+// several requests have the same name, the first one is returned.
+func (s StartedContainers) Lookup(name string) (StartedContainer, bool) {
+	for _, r := range s {
+		if r.Name == name {
+			return r, true
+		}
+	}
+
+	for _, r := range s {
+		if r.ContainerName == name {
+			return r, true
+		}
+	}
+
+	return StartedContainer{}, false
+}
+
+// Get returns the container of the request by name, see Lookup. It returns nil if there is no such request or the
+// container could not be created.
+func (s StartedContainers) Get(name string) Container {
+	r, _ := s.Lookup(name)
+
+	return r.Container
+}
+
+// Err returns the error of the request by name, see Lookup.
+func (s StartedContainers) Err(name string) error {
+	r, _ := s.Lookup(name)
+
+	return r.Err
+}
+
+// Stop stops the started containers in the reverse order of the dependencies, so the containers are stopped before the
+// ones they depend on. The containers in the same dependency level are stopped in parallel.
+func (s StartedContainers) Stop(ctx context.Context, opts ...StopOption) error {
+	errs := stopContainers(ctx, s.stopLevels(), newStopOptions(opts))
+
+	return errs.AsError()
+}
+
+func (s StartedContainers) stopLevels() [][]stopTarget {
+	var levels [][]stopTarget
+
+	for _, r := range s {
+		if r.Container == nil {
+			continue
+		}
+
+		for len(levels) <= r.level {
+			levels = append(levels, nil)
+		}
+
+		levels[r.level] = append(levels[r.level], stopTarget{name: r.Name, container: r.Container})
+	}
+
+	slices.Reverse(levels)
+
+	return levels
+}
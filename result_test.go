@@ -0,0 +1,50 @@
+package testcontainers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/testcontainers-extra"
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+func TestStartedContainers(t *testing.T) {
+	t.Parallel()
+
+	postgres := &mock.Container{}
+	app := &mock.Container{}
+	startErr := errors.New("start error")
+
+	result := testcontainers.StartedContainers{
+		{Name: "postgres", ContainerName: "test_postgres", Container: postgres},
+		{Name: "kafka", ContainerName: "kafka", Err: startErr},
+		{Name: "app", ContainerName: "postgres_app", Container: app, Err: startErr},
+	}
+
+	assert.Equal(t, []testcontainers.Container{postgres, app}, result.Containers())
+
+	actual, ok := result.Lookup("postgres")
+
+	assert.True(t, ok)
+	assert.Equal(t, result[0], actual)
+
+	actual, ok = result.Lookup("postgres_app")
+
+	assert.True(t, ok)
+	assert.Equal(t, result[2], actual)
+
+	actual, ok = result.Lookup("unknown")
+
+	assert.False(t, ok)
+	assert.Empty(t, actual)
+
+	assert.Same(t, postgres, result.Get("test_postgres"))
+	assert.Nil(t, result.Get("kafka"))
+	assert.Nil(t, result.Get("unknown"))
+
+	assert.NoError(t, result.Err("postgres"))
+	assert.Equal(t, startErr, result.Err("app"))
+	assert.NoError(t, result.Err("unknown"))
+}
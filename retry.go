@@ -0,0 +1,171 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// transientErrorMessages are the messages of the errors that are returned as plain text by the docker daemon.
+var transientErrorMessages = []string{
+	"port is already allocated",
+	"address already in use",
+	"TLS handshake timeout",
+	"i/o timeout",
+	"Client.Timeout exceeded",
+	"connection reset by peer",
+}
+
+// Backoff returns the delay before a retry. The retry starts from 1.
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits for the same amount of time before every retry.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff doubles the delay after every retry, the delay never exceeds the max delay.
+func ExponentialBackoff(initial, maxDelay time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		d := initial
+
+		for i := 1; i < retry && d < maxDelay; i++ {
+			d *= 2
+		}
+
+		return min(d, maxDelay)
+	}
+}
+
+// IsTransientError checks whether the error is transient and starting the container again could succeed. Transient
+// errors are the timeouts while pulling images, the port collisions and the 5xx errors of the docker daemon.
+//
+// The errors caused by a canceled context or an exceeded deadline are not transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if cerrdefs.IsInternal(err) || cerrdefs.IsUnavailable(err) {
+		return true
+	}
+
+	var netErr net.Error
+
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := err.Error()
+
+	for _, m := range transientErrorMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type retryOptions struct {
+	maxRetries int
+	backoff    Backoff
+}
+
+// WithRetry retries creating, starting and waiting for the container when it fails with a transient error, see
+// IsTransientError. The container of the failed attempt is terminated before the next attempt, and the next attempt
+// copies the same content of the files, including the ones from readers. The callbacks are not retried.
+func WithRetry(maxRetries int, backoff Backoff) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.retry = retryOptions{
+			maxRetries: maxRetries,
+			backoff:    backoff,
+		}
+	})
+}
+
+// genericContainerWithRetry creates, starts and waits for the container, and retries if the error is transient.
+func genericContainerWithRetry(ctx context.Context, o genericContainerOptions, r testcontainers.GenericContainerRequest) (Container, error) {
+	contents, err := fileContents(r.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	for retry := 0; ; retry++ {
+		// The readers of the files are drained by the previous attempt.
+		r.Files = withFileContents(r.Files, contents)
+
+		c, err := o.genericContainer(ctx, r)
+		if err == nil || retry >= o.retry.maxRetries || !IsTransientError(err) {
+			if err != nil && retry > 0 {
+				err = fmt.Errorf("gave up after %d attempts: %w", retry+1, err)
+			}
+
+			return c, err
+		}
+
+		if c != nil {
+			if tErr := c.Terminate(ctx); tErr != nil {
+				return c, errors.Join(err, &ContainerError{ID: c.GetContainerID(), Phase: PhaseTerminate, Err: tErr})
+			}
+		}
+
+		var delay time.Duration
+
+		if o.retry.backoff != nil {
+			delay = o.retry.backoff(retry + 1)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(err, ctx.Err())
+
+		case <-time.After(delay):
+		}
+	}
+}
+
+// fileContents reads the readers of the files, so that every attempt copies the same content. The readers are already
+// buffered by the fingerprint of the request.
+func fileContents(files []ContainerFile) ([][]byte, error) {
+	contents := make([][]byte, len(files))
+
+	for i, f := range files {
+		if f.Reader == nil {
+			continue
+		}
+
+		content, err := io.ReadAll(f.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file %q: %w", f.ContainerFilePath, err)
+		}
+
+		contents[i] = content
+	}
+
+	return contents, nil
+}
+
+// withFileContents returns the files with new readers of the contents.
+func withFileContents(files []ContainerFile, contents [][]byte) []ContainerFile {
+	files = slices.Clone(files)
+
+	for i := range files {
+		if files[i].Reader != nil {
+			files[i].Reader = bytes.NewReader(contents[i])
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,99 @@
+package testcontainers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/stretchr/testify/assert"
+
+	"go.nhat.io/testcontainers-extra"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	t.Parallel()
+
+	b := testcontainers.ConstantBackoff(time.Second)
+
+	assert.Equal(t, time.Second, b(1))
+	assert.Equal(t, time.Second, b(5))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	b := testcontainers.ExponentialBackoff(time.Second, 5*time.Second)
+
+	assert.Equal(t, time.Second, b(1))
+	assert.Equal(t, 2*time.Second, b(2))
+	assert.Equal(t, 4*time.Second, b(3))
+	assert.Equal(t, 5*time.Second, b(4))
+	assert.Equal(t, 5*time.Second, b(100))
+}
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		error    error
+		expected bool
+	}{
+		{
+			scenario: "nil",
+		},
+		{
+			scenario: "unknown error",
+			error:    errors.New("unknown error"),
+		},
+		{
+			scenario: "not found",
+			error:    fmt.Errorf("create container: %w", cerrdefs.ErrNotFound),
+		},
+		{
+			scenario: "context canceled",
+			error:    fmt.Errorf("wait until ready: %w", context.Canceled),
+		},
+		{
+			scenario: "deadline exceeded",
+			error:    fmt.Errorf("wait until ready: %w", context.DeadlineExceeded),
+		},
+		{
+			scenario: "internal server error",
+			error:    fmt.Errorf("create container: %w", cerrdefs.ErrInternal),
+			expected: true,
+		},
+		{
+			scenario: "service unavailable",
+			error:    fmt.Errorf("create container: %w", cerrdefs.ErrUnavailable),
+			expected: true,
+		},
+		{
+			scenario: "network timeout",
+			error:    fmt.Errorf("pull image: %w", &net.DNSError{IsTimeout: true}),
+			expected: true,
+		},
+		{
+			scenario: "pull timeout",
+			error:    errors.New(`Error response from daemon: Get "https://registry-1.docker.io/v2/": net/http: TLS handshake timeout`),
+			expected: true,
+		},
+		{
+			scenario: "port already allocated",
+			error:    errors.New("Bind for 0.0.0.0:5432 failed: port is already allocated"),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, testcontainers.IsTransientError(tc.error))
+		})
+	}
+}
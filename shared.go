@@ -0,0 +1,211 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const sharedLockPollInterval = 100 * time.Millisecond
+
+type sharedOptions struct {
+	dir string
+}
+
+// WithSharedContainer shares the container with the other processes, for example, the test binaries of the packages
+// run by `go test ./...`. The first process starts the container, the other processes attach to it. The container is
+// terminated when the last process terminates it.
+//
+// The callbacks are run by every process, one process at a time, they should not fail if the container is already set
+// up by another process.
+//
+// The processes are coordinated by file locks in the temporary directory, the requests are identified by their
+// fingerprint and their name. The container is named after the fingerprint if the request has no name. The file locks
+// are only supported on unix, the container fails to start with errors.ErrUnsupported on the other platforms.
+func WithSharedContainer() GenericContainerOption {
+	return WithSharedContainerDir(filepath.Join(os.TempDir(), "testcontainers-extra"))
+}
+
+// WithSharedContainerDir is WithSharedContainer with a custom directory for the lock files.
+func WithSharedContainerDir(dir string) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.shared = &sharedOptions{dir: dir}
+	})
+}
+
+// shareContainer locks the request for starting or attaching to the shared container.
+func shareContainer(ctx context.Context, r *testcontainers.GenericContainerRequest, o *sharedOptions, fp string) (*sharedLock, error) {
+	key := fp
+
+	if r.Name == "" {
+		r.Name = fingerprintContainerName(fp)
+	} else {
+		key = fmt.Sprintf("%s-%s", sanitizeFileName(r.Name), fp[:16])
+	}
+
+	l, err := acquireSharedLock(ctx, o.dir, key)
+	if err != nil {
+		return nil, fmt.Errorf("could not lock shared container %q: %w", r.Name, err)
+	}
+
+	r.Reuse = true
+
+	return l, nil
+}
+
+// sharedLock coordinates the processes sharing a container. The guard file is locked exclusively while a process
+// starts, attaches to or terminates the container. Every process using the container holds a shared lock on the users
+// file. The locks are released by the operating system when a process dies.
+type sharedLock struct {
+	mu    sync.Mutex
+	guard *os.File
+	users *os.File
+}
+
+// acquireSharedLock locks the guard and registers a new user of the container.
+func acquireSharedLock(ctx context.Context, dir, key string) (*sharedLock, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	guard, err := os.OpenFile(filepath.Join(dir, key+".lock"), os.O_CREATE|os.O_RDWR, 0o600) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+
+	l := &sharedLock{guard: guard}
+
+	if err := l.lockGuard(ctx); err != nil {
+		_ = l.close() // nolint: errcheck
+
+		return nil, err
+	}
+
+	l.users, err = os.OpenFile(filepath.Join(dir, key+".users"), os.O_CREATE|os.O_RDWR, 0o600) // nolint: gosec
+	if err != nil {
+		_ = l.close() // nolint: errcheck
+
+		return nil, err
+	}
+
+	if _, err := lockFile(l.users, false); err != nil {
+		_ = l.close() // nolint: errcheck
+
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// lockGuard waits until the guard is locked exclusively or the context is done.
+func (l *sharedLock) lockGuard(ctx context.Context) error {
+	for {
+		ok, err := lockFile(l.guard, true)
+		if err != nil || ok {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-time.After(sharedLockPollInterval):
+		}
+	}
+}
+
+func (l *sharedLock) unlockGuard() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.guard != nil {
+		_ = unlockFile(l.guard) // nolint: errcheck
+	}
+}
+
+// release unregisters the user and tells whether it was the last one. The guard must be locked.
+func (l *sharedLock) release() (bool, error) {
+	if err := unlockFile(l.users); err != nil {
+		return false, err
+	}
+
+	return lockFile(l.users, true)
+}
+
+// close closes the files, the locks are released.
+func (l *sharedLock) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var err error
+
+	for _, f := range []*os.File{l.users, l.guard} {
+		if f == nil {
+			continue
+		}
+
+		if cErr := f.Close(); err == nil {
+			err = cErr
+		}
+	}
+
+	l.users, l.guard = nil, nil
+
+	return err
+}
+
+// attach wraps the container so that terminating it only releases the lock unless it is the last user. The lock is
+// released right away if there is no container.
+func (l *sharedLock) attach(c Container) Container {
+	if c == nil {
+		_ = l.close() // nolint: errcheck
+
+		return nil
+	}
+
+	return &sharedContainer{Container: c, lock: l}
+}
+
+// sharedContainer is a container shared with other processes.
+type sharedContainer struct {
+	Container
+
+	mu   sync.Mutex
+	lock *sharedLock
+}
+
+// Terminate terminates the container if there is no other process using it.
+func (c *sharedContainer) Terminate(ctx context.Context, opts ...testcontainers.TerminateOption) error {
+	c.mu.Lock()
+	l := c.lock
+	c.lock = nil
+	c.mu.Unlock()
+
+	if l == nil {
+		return nil
+	}
+
+	defer l.close() // nolint: errcheck
+
+	if err := l.lockGuard(ctx); err != nil {
+		return fmt.Errorf("could not lock shared container: %w", err)
+	}
+
+	last, err := l.release()
+	if err != nil {
+		return fmt.Errorf("could not release shared container: %w", err)
+	}
+
+	if !last {
+		leaks.untrack(c.GetContainerID())
+
+		return nil
+	}
+
+	return c.Container.Terminate(ctx, opts...)
+}
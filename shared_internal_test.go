@@ -0,0 +1,151 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+func TestStartGenericContainer_Shared(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	mc := mock.MockContainer(func(c *mock.Container) {
+		c.On("GetContainerID").
+			Return("42").Once()
+
+		c.On("Terminate", testifymock.Anything).
+			Return(nil).Once()
+	})(t)
+
+	var (
+		requests []testcontainers.GenericContainerRequest
+		called   int
+	)
+
+	opts := []GenericContainerOption{
+		WithSharedContainerDir(dir),
+		WithCallback(func(context.Context, Container, ContainerRequest) error {
+			called++
+
+			return nil
+		}),
+		fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			requests = append(requests, req)
+
+			return mc, nil
+		}),
+	}
+
+	request := ContainerRequest{Image: "postgres:16"}
+
+	first, err := StartGenericContainer(context.Background(), request, opts...)
+	require.NoError(t, err)
+
+	second, err := StartGenericContainer(context.Background(), request, opts...)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.True(t, requests[0].Reuse)
+	assert.True(t, strings.HasPrefix(requests[0].Name, fingerprintContainerNamePrefix))
+	assert.Equal(t, requests[0].Name, requests[1].Name)
+
+	// The callbacks are run by every user.
+	assert.Equal(t, 2, called)
+
+	// The container is terminated by the last user.
+	require.NoError(t, first.Terminate(context.Background()))
+	mc.AssertNotCalled(t, "Terminate", testifymock.Anything)
+
+	require.NoError(t, first.Terminate(context.Background()), "terminating twice does nothing")
+	require.NoError(t, second.Terminate(context.Background()))
+
+	// The next user starts over.
+	third, err := StartGenericContainer(context.Background(), request, opts...)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, called)
+
+	mc.On("Terminate", testifymock.Anything).
+		Return(nil).Once()
+
+	require.NoError(t, third.Terminate(context.Background()))
+}
+
+func TestStartGenericContainer_SharedWithName(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var name string
+
+	c, err := StartGenericContainer(context.Background(), ContainerRequest{Name: "postgres", Image: "postgres:16"},
+		WithNamePrefix("test"),
+		WithSharedContainerDir(dir),
+		fakeGenericContainer(func(_ context.Context, req testcontainers.GenericContainerRequest) (Container, error) {
+			name = req.Name
+
+			return nil, errors.New("start error")
+		}),
+	)
+	require.EqualError(t, err, "start error")
+
+	assert.Nil(t, c)
+	assert.Equal(t, "test_postgres", name)
+
+	// The lock is released when the container could not be started.
+	l, err := acquireSharedLock(context.Background(), dir, name+"-"+mustFingerprint(t, ContainerRequest{Image: "postgres:16"})[:16])
+	require.NoError(t, err)
+
+	defer l.close() // nolint: errcheck
+
+	last, err := l.release()
+	require.NoError(t, err)
+
+	assert.True(t, last)
+}
+
+func TestStartGenericContainer_SharedLockCanceled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	request := ContainerRequest{Image: "postgres:16"}
+	fp := mustFingerprint(t, request)
+
+	l, err := acquireSharedLock(context.Background(), dir, fp)
+	require.NoError(t, err)
+
+	defer l.close() // nolint: errcheck
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c, err := StartGenericContainer(ctx, request,
+		WithSharedContainerDir(dir),
+		fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+			return mock.NopContainer(t), nil
+		}),
+	)
+
+	assert.Nil(t, c)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "could not lock shared container")
+}
+
+func mustFingerprint(t *testing.T, r ContainerRequest) string {
+	t.Helper()
+
+	fp, err := fingerprintRequest(&r)
+	require.NoError(t, err)
+
+	return fp
+}
@@ -0,0 +1,111 @@
+package testcontainers
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// StopOption is option for stopping multiple containers at once.
+type StopOption interface {
+	applyStopOptions(o *stopOptions)
+}
+
+type stopOptionFunc func(o *stopOptions)
+
+func (f stopOptionFunc) applyStopOptions(o *stopOptions) {
+	f(o)
+}
+
+type stopOptions struct {
+	terminateOptions []testcontainers.TerminateOption
+	maxConcurrency   int
+	reverseOrder     bool
+}
+
+func newStopOptions(opts []StopOption) stopOptions {
+	o := stopOptions{}
+
+	for _, opt := range opts {
+		opt.applyStopOptions(&o)
+	}
+
+	return o
+}
+
+// stopTarget is a container to stop, the name is the name of the request that starts the container, if any.
+type stopTarget struct {
+	name      string
+	container Container
+}
+
+// stopContainers stops the containers level by level, the containers in the same level are stopped in parallel.
+func stopContainers(ctx context.Context, levels [][]stopTarget, o stopOptions) errorCollection {
+	var mu sync.Mutex
+
+	errs := make(errorCollection, 0)
+
+	var sem chan struct{}
+
+	if o.maxConcurrency > 0 {
+		sem = make(chan struct{}, o.maxConcurrency)
+	}
+
+	for _, level := range levels {
+		var wg sync.WaitGroup
+
+		for _, t := range level {
+			if t.container == nil {
+				continue
+			}
+
+			if sem != nil {
+				sem <- struct{}{}
+			}
+
+			wg.Add(1)
+
+			go func(t stopTarget) {
+				defer wg.Done()
+
+				if sem != nil {
+					defer func() { <-sem }()
+				}
+
+				if err := t.container.Terminate(ctx, o.terminateOptions...); err != nil {
+					mu.Lock()
+					defer mu.Unlock()
+
+					errs.Append(&ContainerError{Name: t.name, ID: t.container.GetContainerID(), Phase: PhaseTerminate, Err: err})
+				}
+			}(t)
+		}
+
+		wg.Wait()
+	}
+
+	return errs
+}
+
+// containerStopLevels puts all the containers in one level, or in one level each in the reverse order if required.
+func containerStopLevels(containers []Container, reverseOrder bool) [][]stopTarget {
+	if !reverseOrder {
+		level := make([]stopTarget, 0, len(containers))
+
+		for _, c := range containers {
+			level = append(level, stopTarget{container: c})
+		}
+
+		return [][]stopTarget{level}
+	}
+
+	levels := make([][]stopTarget, 0, len(containers))
+
+	for _, c := range slices.Backward(containers) {
+		levels = append(levels, []stopTarget{{container: c}})
+	}
+
+	return levels
+}
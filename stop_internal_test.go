@@ -0,0 +1,137 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+// stopRecorder records the order in which the containers are stopped.
+type stopRecorder struct {
+	mu      sync.Mutex
+	stopped []string
+}
+
+func (r *stopRecorder) container(t *testing.T, id string, err error, opts ...any) *mock.Container {
+	t.Helper()
+
+	return mock.MockContainer(func(c *mock.Container) {
+		args := append([]any{testifymock.Anything}, opts...)
+
+		c.On("Terminate", args...).
+			Run(func(testifymock.Arguments) {
+				r.mu.Lock()
+				defer r.mu.Unlock()
+
+				r.stopped = append(r.stopped, id)
+			}).
+			Return(err).Once()
+
+		if err != nil {
+			c.On("GetContainerID").
+				Return(id).Once()
+		}
+	})(t)
+}
+
+func TestStopGenericContainersWithOptions_ReverseOrder(t *testing.T) {
+	t.Parallel()
+
+	r := &stopRecorder{}
+
+	err := StopGenericContainersWithOptions(context.Background(),
+		[]Container{
+			r.container(t, "postgres", nil),
+			nil,
+			r.container(t, "kafka", errors.New("terminate error")),
+			r.container(t, "app", nil),
+		},
+		WithReverseOrder(),
+	)
+
+	assert.EqualError(t, err, `could not stop container "kafka": terminate error`)
+	assert.Equal(t, []string{"app", "kafka", "postgres"}, r.stopped)
+}
+
+func TestStopGenericContainersWithOptions_TerminateOptions(t *testing.T) {
+	t.Parallel()
+
+	r := &stopRecorder{}
+	isStopTimeout := testifymock.MatchedBy(func(opt testcontainers.TerminateOption) bool {
+		o := testcontainers.NewTerminateOptions(context.Background(), opt)
+
+		return o.StopTimeout() != nil && *o.StopTimeout() == time.Second
+	})
+
+	err := StopGenericContainersWithOptions(context.Background(),
+		[]Container{r.container(t, "postgres", nil, isStopTimeout)},
+		WithTerminateOptions(testcontainers.StopTimeout(time.Second)),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"postgres"}, r.stopped)
+}
+
+func TestStopGenericContainersWithOptions_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+	)
+
+	containers := make([]Container, 0, 6)
+
+	for range 6 {
+		containers = append(containers, mock.MockContainer(func(c *mock.Container) {
+			c.On("Terminate", testifymock.Anything).
+				Run(func(testifymock.Arguments) {
+					mu.Lock()
+					running++
+					peak = max(peak, running)
+					mu.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					mu.Lock()
+					running--
+					mu.Unlock()
+				}).
+				Return(nil).Once()
+		})(t))
+	}
+
+	err := StopGenericContainersWithOptions(context.Background(), containers, WithMaxConcurrency(2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, peak)
+}
+
+func TestStartedContainers_Stop(t *testing.T) {
+	t.Parallel()
+
+	r := &stopRecorder{}
+
+	result := StartedContainers{
+		{Name: "postgres", Container: r.container(t, "postgres", nil)},
+		{Name: "app", Container: r.container(t, "app", errors.New("terminate error")), level: 2},
+		{Name: "kafka", Container: r.container(t, "kafka", nil), level: 1},
+		{Name: "zookeeper", level: 0},
+	}
+
+	err := result.Stop(context.Background())
+
+	expected := Errors{&ContainerError{Name: "app", ID: "app", Phase: PhaseTerminate, Err: errors.New("terminate error")}}
+
+	assert.Equal(t, expected, err)
+	assert.Equal(t, []string{"app", "kafka", "postgres"}, r.stopped)
+}
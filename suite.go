@@ -0,0 +1,218 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// TestingM is the interface of testing.M.
+type TestingM interface {
+	Run() int
+}
+
+// Suite starts the containers that are shared by all the tests of a package before running the tests, and stops them
+// after that. The host and port environment variables are populated for every container, see PopulateHostPortEnv.
+//
+// For example:
+//
+//	var suite = testcontainers.NewSuite(
+//		testcontainers.StartGenericContainerRequest{Request: postgres},
+//	)
+//
+//	func TestMain(m *testing.M) {
+//		suite.Main(m)
+//	}
+//
+//	func TestRepository(t *testing.T) {
+//		c := suite.Container("postgres")
+//	}
+type Suite struct {
+	requests     []StartGenericContainerRequest
+	batchOptions []BatchOption
+	stopOptions  []StopOption
+	checkLeaks   func() error
+
+	exit   func(code int)
+	output io.Writer
+	notify func(c chan<- os.Signal)
+
+	mu       sync.Mutex
+	result   StartedContainers
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// NewSuite creates a new suite.
+func NewSuite(requests ...StartGenericContainerRequest) *Suite {
+	return &Suite{
+		requests: requests,
+		exit:     os.Exit,
+		output:   os.Stderr,
+		notify: func(c chan<- os.Signal) {
+			signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		},
+	}
+}
+
+// WithBatchOptions sets the options for starting the containers.
+func (s *Suite) WithBatchOptions(opts ...BatchOption) *Suite {
+	s.batchOptions = append(s.batchOptions, opts...)
+
+	return s
+}
+
+// WithStopOptions sets the options for stopping the containers.
+func (s *Suite) WithStopOptions(opts ...StopOption) *Suite {
+	s.stopOptions = append(s.stopOptions, opts...)
+
+	return s
+}
+
+// WithLeakCheck fails the suite if there are containers that are started by this package but not terminated when the
+// tests complete, see CheckLeaks. The leaks are printed to the output.
+func (s *Suite) WithLeakCheck() *Suite {
+	s.checkLeaks = CheckLeaks
+
+	return s
+}
+
+// Main runs the suite and exits with its exit code. It is supposed to be called in TestMain.
+func (s *Suite) Main(m TestingM) {
+	s.exit(s.Run(m))
+}
+
+// Run starts the containers, runs the tests, then stops the containers and returns the exit code.
+//
+// The containers are also stopped if the suite panics or is interrupted by a signal. A panic in a test crashes the
+// process before the containers could be stopped, they are then left to the testcontainers reaper.
+func (s *Suite) Run(m TestingM) (code int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+
+	s.notify(sigs)
+
+	defer signal.Stop(sigs)
+	defer close(done)
+
+	go s.handleSignals(sigs, done, started, cancel)
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.printf("tests panicked: %v\n", r)
+
+			code = 2
+		}
+
+		if err := s.stop(); err != nil {
+			s.printf("could not stop containers: %s\n", err.Error())
+
+			if code == 0 {
+				code = 1
+			}
+		}
+
+		if s.checkLeaks == nil {
+			return
+		}
+
+		if err := s.checkLeaks(); err != nil {
+			s.printf("%s\n", err.Error())
+
+			if code == 0 {
+				code = 1
+			}
+		}
+	}()
+
+	err := s.start(ctx)
+
+	close(started)
+
+	if err != nil {
+		s.printf("could not start containers: %s\n", err.Error())
+
+		return 1
+	}
+
+	return m.Run()
+}
+
+// handleSignals stops the containers and exits when the suite is interrupted.
+func (s *Suite) handleSignals(sigs <-chan os.Signal, done, started <-chan struct{}, cancel context.CancelFunc) {
+	select {
+	case <-done:
+		return
+
+	case sig := <-sigs:
+		cancel()
+		<-started
+
+		if err := s.stop(); err != nil {
+			s.printf("could not stop containers: %s\n", err.Error())
+		}
+
+		s.printf("tests are interrupted by %s\n", sig)
+		s.exit(exitCodeForSignal(sig))
+	}
+}
+
+func (s *Suite) start(ctx context.Context) error {
+	requests := make([]StartGenericContainerRequest, len(s.requests))
+
+	for i, r := range s.requests {
+		r.Options = append(r.Options[:len(r.Options):len(r.Options)], PopulateHostPortEnv)
+		requests[i] = r
+	}
+
+	result, err := StartGenericContainerBatch(ctx, requests, s.batchOptions...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.result = result
+
+	return err
+}
+
+func (s *Suite) stop() error {
+	s.stopOnce.Do(func() {
+		s.stopErr = s.Containers().Stop(context.Background(), s.stopOptions...)
+	})
+
+	return s.stopErr
+}
+
+func (s *Suite) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(s.output, format, args...)
+}
+
+// Containers returns the results of starting the containers.
+func (s *Suite) Containers() StartedContainers {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.result
+}
+
+// Container returns the container by the name of the request or the name of the container, see StartedContainers.Get.
+// It returns nil if there is no such container.
+func (s *Suite) Container(name string) Container {
+	return s.Containers().Get(name)
+}
+
+func exitCodeForSignal(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+
+	return 1
+}
@@ -0,0 +1,191 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+type testingMFunc func() int
+
+func (f testingMFunc) Run() int {
+	return f()
+}
+
+// newTestSuite creates a suite that does not exit and does not listen to the signals.
+func newTestSuite(requests ...StartGenericContainerRequest) (*Suite, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+
+	s := NewSuite(requests...)
+	s.output = out
+	s.exit = func(int) {}
+	s.notify = func(chan<- os.Signal) {}
+
+	return s, out
+}
+
+func suiteRequest(name string, c Container, err error) StartGenericContainerRequest {
+	return StartGenericContainerRequest{
+		Request: ContainerRequest{Name: name},
+		Options: []GenericContainerOption{
+			fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+				return c, err
+			}),
+		},
+	}
+}
+
+func mockSuiteContainer(c *mock.Container) {
+	c.On("Ports", testifymock.Anything).
+		Return(nat.PortMap{}, nil).Once()
+
+	c.On("Terminate", testifymock.Anything).
+		Return(nil).Once()
+}
+
+func TestSuite_Run(t *testing.T) {
+	t.Parallel()
+
+	postgres := mock.MockContainer(mockSuiteContainer)(t)
+
+	s, out := newTestSuite(suiteRequest("postgres", postgres, nil))
+
+	var actual Container
+
+	code := s.Run(testingMFunc(func() int {
+		actual = s.Container("postgres")
+
+		return 3
+	}))
+
+	assert.Equal(t, 3, code)
+	assert.Same(t, postgres, actual)
+	assert.Empty(t, out.String())
+}
+
+func TestSuite_RunStartError(t *testing.T) {
+	t.Parallel()
+
+	postgres := mock.MockContainer(mockSuiteContainer)(t)
+
+	s, out := newTestSuite(
+		suiteRequest("postgres", postgres, nil),
+		suiteRequest("kafka", nil, errors.New("start error")),
+	)
+
+	code := s.Run(testingMFunc(func() int {
+		assert.Fail(t, "tests should not run")
+
+		return 0
+	}))
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "could not start containers: could not start container \"kafka\": start error\n", out.String())
+}
+
+func TestSuite_RunPanicAndStopError(t *testing.T) {
+	t.Parallel()
+
+	postgres := mock.MockContainer(func(c *mock.Container) {
+		c.On("Ports", testifymock.Anything).
+			Return(nat.PortMap{}, nil).Once()
+
+		c.On("Terminate", testifymock.Anything).
+			Return(errors.New("terminate error")).Once()
+
+		c.On("GetContainerID").
+			Return("42").Once()
+	})(t)
+
+	s, out := newTestSuite(suiteRequest("postgres", postgres, nil))
+
+	code := s.Run(testingMFunc(func() int {
+		panic("test panic")
+	}))
+
+	expected := "tests panicked: test panic\n" +
+		"could not stop containers: could not stop container \"42\": terminate error\n"
+
+	assert.Equal(t, 2, code)
+	assert.Equal(t, expected, out.String())
+}
+
+func TestSuite_RunLeaks(t *testing.T) {
+	t.Parallel()
+
+	postgres := mock.MockContainer(mockSuiteContainer)(t)
+
+	s, out := newTestSuite(suiteRequest("postgres", postgres, nil))
+	s.WithLeakCheck()
+	s.checkLeaks = func() error {
+		return &LeakError{Leaks: []Leak{{ID: "42", Name: "kafka", StartedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}}}
+	}
+
+	code := s.Run(testingMFunc(func() int {
+		return 0
+	}))
+
+	expected := "1 container(s) are not terminated\n\n" +
+		"container \"kafka\" (42) started at 2020-01-02T03:04:05Z\n"
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, expected, out.String())
+}
+
+func TestSuite_Main(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestSuite()
+
+	var actual int
+
+	s.exit = func(code int) {
+		actual = code
+	}
+
+	s.Main(testingMFunc(func() int {
+		return 1
+	}))
+
+	assert.Equal(t, 1, actual)
+}
+
+func TestSuite_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	postgres := mock.MockContainer(mockSuiteContainer)(t)
+
+	s, out := newTestSuite(suiteRequest("postgres", postgres, nil))
+
+	var sigs chan<- os.Signal
+
+	exitCode := make(chan int, 1)
+
+	s.notify = func(c chan<- os.Signal) {
+		sigs = c
+	}
+
+	s.exit = func(code int) {
+		exitCode <- code
+	}
+
+	code := s.Run(testingMFunc(func() int {
+		sigs <- syscall.SIGTERM
+
+		return <-exitCode
+	}))
+
+	assert.Equal(t, 128+int(syscall.SIGTERM), code)
+	assert.Equal(t, "tests are interrupted by terminated\n", out.String())
+}
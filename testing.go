@@ -0,0 +1,155 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// deadlineGracePeriod is reserved before the deadline of the test so the failure could be reported before the test
+	// times out.
+	deadlineGracePeriod = 5 * time.Second
+	// diagnosticsLogsTail is the number of log lines in the diagnostics.
+	diagnosticsLogsTail = 50
+)
+
+// StartGenericContainerT starts a new generic container for a test. The container is terminated when the test and all
+// its subtests complete. The test fails immediately with the diagnostics of the container if it could not be started.
+//
+// The context of the start ends shortly before the deadline of the test, if any.
+func StartGenericContainerT(tb testing.TB, request ContainerRequest, opts ...GenericContainerOption) Container {
+	tb.Helper()
+
+	ctx, cancel := testContext(tb)
+	defer cancel()
+
+	var c Container
+
+	// The cleanup is registered before starting the container so that it runs after the cleanups registered by the
+	// options, such as WithFailureDump.
+	tb.Cleanup(func() {
+		if c == nil {
+			return
+		}
+
+		if err := c.Terminate(context.Background()); err != nil {
+			tb.Errorf("could not stop container %q: %s", request.Name, err.Error())
+		}
+	})
+
+	c, err := StartGenericContainer(ctx, request, append(opts[:len(opts):len(opts)], withOriginTest(tb.Name()))...)
+	if err != nil {
+		tb.Fatalf("could not start container %q (%s): %s%s", request.Name, request.Image, err.Error(), diagnostics(c))
+	}
+
+	return c
+}
+
+// StartGenericContainersT starts multiple generic containers for a test, see StartGenericContainerBatch. The containers
+// are terminated in the reverse order of the dependencies when the test and all its subtests complete. The test fails
+// immediately with the diagnostics of the containers if any of them could not be started.
+//
+// The context of the start ends shortly before the deadline of the test, if any.
+func StartGenericContainersT(tb testing.TB, requests []StartGenericContainerRequest, opts ...BatchOption) StartedContainers {
+	tb.Helper()
+
+	ctx, cancel := testContext(tb)
+	defer cancel()
+
+	var result StartedContainers
+
+	// The cleanup is registered before starting the containers so that it runs after the cleanups registered by the
+	// options, such as WithFailureDump.
+	tb.Cleanup(func() {
+		if err := result.Stop(context.Background()); err != nil {
+			tb.Errorf("could not stop containers: %s", err.Error())
+		}
+	})
+
+	result, err := StartGenericContainerBatch(ctx, requests, append(opts[:len(opts):len(opts)], withBatchOriginTest(tb.Name()))...)
+	if err != nil {
+		var sb strings.Builder
+
+		sb.WriteString("could not start containers: ")
+		sb.WriteString(err.Error())
+
+		for _, r := range result {
+			if r.Err != nil && r.Container != nil {
+				sb.WriteString(fmt.Sprintf("\n\ncontainer %q:", r.Name))
+				sb.WriteString(diagnostics(r.Container))
+			}
+		}
+
+		tb.Fatal(sb.String())
+	}
+
+	return result
+}
+
+// withOriginTest sets the test that starts the container.
+func withOriginTest(name string) GenericContainerOption {
+	return genericContainerOptionFunc(func(o *genericContainerOptions) {
+		o.origin.test = name
+	})
+}
+
+// withBatchOriginTest sets the test that starts the containers.
+func withBatchOriginTest(name string) BatchOption {
+	return batchOptionFunc(func(o *batchOptions) {
+		o.test = name
+	})
+}
+
+// testContext returns a context that ends shortly before the deadline of the test, if any.
+func testContext(tb testing.TB) (context.Context, context.CancelFunc) {
+	t, ok := tb.(interface{ Deadline() (time.Time, bool) })
+	if !ok {
+		return context.WithCancel(context.Background())
+	}
+
+	deadline, ok := t.Deadline()
+	if !ok {
+		return context.WithCancel(context.Background())
+	}
+
+	if d := time.Until(deadline); d > 2*deadlineGracePeriod {
+		deadline = deadline.Add(-deadlineGracePeriod)
+	}
+
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// diagnostics describes the state and the last logs of the container.
+func diagnostics(c Container) string {
+	if c == nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("\nid: %s", c.GetContainerID()))
+
+	if state, err := c.State(ctx); err != nil {
+		sb.WriteString(fmt.Sprintf("\nstate: could not get state: %s", err.Error()))
+	} else if state != nil {
+		sb.WriteString(fmt.Sprintf("\nstate: %s (exit code: %d)", state.Status, state.ExitCode))
+
+		if state.Error != "" {
+			sb.WriteString(fmt.Sprintf("\nerror: %s", state.Error))
+		}
+	}
+
+	if logs, err := readLogs(ctx, c, diagnosticsLogsTail); err != nil {
+		sb.WriteString(fmt.Sprintf("\nlogs: could not get logs: %s", err.Error()))
+	} else if logs != "" {
+		sb.WriteString(fmt.Sprintf("\nlogs (last %d lines):\n%s", diagnosticsLogsTail, logs))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,256 @@
+package testcontainers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/testcontainers/testcontainers-go"
+
+	"go.nhat.io/testcontainers-extra/mock"
+)
+
+// fakeTB records the failures and the cleanups of a test.
+type fakeTB struct {
+	testing.TB
+
+	name     string
+	mu       sync.Mutex
+	cleanups []func()
+	fatal    string
+	errors   []string
+	logs     []string
+	failed   bool
+	deadline time.Time
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Name() string {
+	if t.name != "" {
+		return t.name
+	}
+
+	return "TestFake"
+}
+
+func (t *fakeTB) Cleanup(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failed = true
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Logf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Fatal(args ...any) {
+	t.mu.Lock()
+	t.failed = true
+	t.fatal = fmt.Sprint(args...)
+	t.mu.Unlock()
+
+	runtime.Goexit()
+}
+
+func (t *fakeTB) Fatalf(format string, args ...any) {
+	t.Fatal(fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Failed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.failed
+}
+
+func (t *fakeTB) Deadline() (time.Time, bool) {
+	return t.deadline, !t.deadline.IsZero()
+}
+
+// run runs the function as a test, then runs the cleanups in the reverse order.
+func (t *fakeTB) run(f func()) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		f()
+	}()
+
+	<-done
+
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestStartGenericContainerT_Success(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{deadline: time.Now().Add(time.Minute)}
+
+	mc := mock.MockContainer(func(c *mock.Container) {
+		c.On("Terminate", testifymock.Anything).
+			Return(nil).Once()
+	})(t)
+
+	var (
+		actual   Container
+		deadline time.Time
+	)
+
+	tb.run(func() {
+		actual = StartGenericContainerT(tb, ContainerRequest{Name: "postgres"},
+			fakeGenericContainer(func(ctx context.Context, _ testcontainers.GenericContainerRequest) (Container, error) {
+				deadline, _ = ctx.Deadline()
+
+				return mc, nil
+			}),
+		)
+	})
+
+	assert.Same(t, mc, actual)
+	assert.False(t, tb.Failed())
+	assert.WithinDuration(t, tb.deadline.Add(-deadlineGracePeriod), deadline, time.Millisecond)
+}
+
+func TestStartGenericContainerT_DoesNotChangeOptions(t *testing.T) {
+	t.Parallel()
+
+	start := fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+		return nil, errors.New("start error")
+	})
+
+	// The options have spare capacity, so appending to them would write to the shared backing array.
+	opts := make([]GenericContainerOption, 1, 2)
+	opts[0] = start
+
+	batchOpts := make([]BatchOption, 0, 1)
+
+	tb := &fakeTB{}
+
+	tb.run(func() {
+		StartGenericContainerT(tb, ContainerRequest{Name: "postgres"}, opts...)
+	})
+
+	tb.run(func() {
+		StartGenericContainersT(tb, []StartGenericContainerRequest{{Request: ContainerRequest{Name: "postgres"}, Options: opts}}, batchOpts...)
+	})
+
+	assert.Nil(t, opts[:2][1])
+	assert.Nil(t, batchOpts[:1][0])
+}
+
+func TestStartGenericContainerT_Failure(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+
+	mc := mock.MockContainer(func(c *mock.Container) {
+		c.On("GetContainerID").
+			Return("42")
+
+		c.On("State", testifymock.Anything).
+			Return(&container.State{Status: "exited", ExitCode: 1}, nil).Once()
+
+		c.On("Logs", testifymock.Anything).
+			Return(io.NopCloser(strings.NewReader("FATAL: database does not exist\n")), nil).Once()
+
+		c.On("Terminate", testifymock.Anything).
+			Return(errors.New("terminate error")).Once()
+	})(t)
+
+	tb.run(func() {
+		StartGenericContainerT(tb, ContainerRequest{Name: "postgres", Image: "postgres:12-alpine"},
+			fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+				return mc, errors.New("wait error")
+			}),
+		)
+
+		assert.Fail(t, "the test should stop")
+	})
+
+	expected := "could not start container \"postgres\" (postgres:12-alpine): wait error\n" +
+		"id: 42\n" +
+		"state: exited (exit code: 1)\n" +
+		"logs (last 50 lines):\n" +
+		"FATAL: database does not exist"
+
+	assert.Equal(t, expected, tb.fatal)
+	assert.Equal(t, []string{`could not stop container "postgres": terminate error`}, tb.errors)
+}
+
+func TestStartGenericContainersT(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+	r := &stopRecorder{}
+
+	postgres := r.container(t, "postgres", nil)
+	app := r.container(t, "app", nil)
+
+	app.On("GetContainerID").
+		Return("42")
+
+	app.On("State", testifymock.Anything).
+		Return(nil, errors.New("state error")).Once()
+
+	app.On("Logs", testifymock.Anything).
+		Return(nil, errors.New("logs error")).Once()
+
+	tb.run(func() {
+		StartGenericContainersT(tb, []StartGenericContainerRequest{
+			{
+				Request: ContainerRequest{Name: "postgres"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+						return postgres, nil
+					}),
+				},
+			},
+			{
+				Request: ContainerRequest{Name: "app"},
+				Options: []GenericContainerOption{
+					fakeGenericContainer(func(context.Context, testcontainers.GenericContainerRequest) (Container, error) {
+						return app, errors.New("wait error")
+					}),
+				},
+				DependsOn: []string{"postgres"},
+			},
+		})
+
+		assert.Fail(t, "the test should stop")
+	})
+
+	expected := "could not start containers: could not start container \"app\": wait error\n\n" +
+		"container \"app\":\n" +
+		"id: 42\n" +
+		"state: could not get state: state error\n" +
+		"logs: could not get logs: logs error"
+
+	assert.Equal(t, expected, tb.fatal)
+	assert.Empty(t, tb.errors)
+	assert.Equal(t, []string{"app", "postgres"}, r.stopped)
+}
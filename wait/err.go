@@ -1,7 +1,17 @@
 package wait
 
-// ErrMaxRetriesExceeded indicates that the number of max retries exceeded.
-const ErrMaxRetriesExceeded healthCheckError = "max retries exceeded"
+const (
+	// ErrMaxRetriesExceeded indicates that the number of max retries exceeded.
+	ErrMaxRetriesExceeded healthCheckError = "max retries exceeded"
+	// ErrNoHealthCheck indicates that the container has no health check.
+	ErrNoHealthCheck healthCheckError = "no health check configured"
+	// ErrUnhealthy indicates that Docker reports the container as unhealthy.
+	ErrUnhealthy healthCheckError = "container is unhealthy"
+	// ErrHealthCheckMismatch indicates that the health check of the container is not the one of the strategy.
+	ErrHealthCheckMismatch healthCheckError = "health check of the container does not match"
+	// ErrInvalidHealthCheckTest indicates that the test of the health check is not supported.
+	ErrInvalidHealthCheckTest healthCheckError = "invalid health check test"
+)
 
 type healthCheckError string
 
@@ -3,9 +3,10 @@ package wait
 import (
 	"context"
 	"errors"
-	"fmt"
+	"slices"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
@@ -20,11 +21,16 @@ var _ wait.Strategy = (*HealthCheckStrategy)(nil)
 
 // HealthCheckStrategy is a strategy for doing health check.
 type HealthCheckStrategy struct {
-	test         HealthCheckTest
-	testInterval time.Duration
-	testTimeout  time.Duration
-	retries      int
-	startPeriod  time.Duration
+	test          HealthCheckTest
+	testInterval  time.Duration
+	testTimeout   time.Duration
+	retries       int
+	startPeriod   time.Duration
+	startInterval time.Duration
+
+	// healthTest is the test in the format of the health config of Docker, it is only set when the test runs a command.
+	healthTest []string
+	native     bool
 }
 
 // WithTestInterval sets the interval between retries.
@@ -56,23 +62,106 @@ func (s *HealthCheckStrategy) WithStartPeriod(duration time.Duration) *HealthChe
 	return s
 }
 
-func (s *HealthCheckStrategy) testTarget(target wait.StrategyTarget) (success bool, err error) {
+// WithStartInterval sets the interval between retries during the start period. The test interval is used if it is not
+// set.
+func (s *HealthCheckStrategy) WithStartInterval(interval time.Duration) *HealthCheckStrategy {
+	s.startInterval = interval
+
+	return s
+}
+
+// WithNativeHealthCheck lets Docker run the health check instead of the test process. The health check is applied to the
+// container as its health config when the container is started by StartGenericContainer, and the strategy only watches
+// the health status that Docker reports, see ForNativeHealthCheck. It only works with the health checks that run a
+// command, such as ForHealthCheckCmd.
+//
+// The strategy could be nested in wait.ForAll, but a container has only one health check. The strategy fails with
+// ErrHealthCheckMismatch if the health check of the container is not its own, for example when it is not started by
+// StartGenericContainer and the image has another HEALTHCHECK.
+func (s *HealthCheckStrategy) WithNativeHealthCheck() *HealthCheckStrategy {
+	s.native = true
+
+	return s
+}
+
+// HealthConfig converts the health check to the health config of Docker. It returns nil if the health check does not
+// run a command, such as the ones created by ForHealthCheck.
+func (s *HealthCheckStrategy) HealthConfig() *container.HealthConfig {
+	if len(s.healthTest) == 0 {
+		return nil
+	}
+
+	return &container.HealthConfig{
+		Test:          slices.Clone(s.healthTest),
+		Interval:      s.testInterval,
+		Timeout:       s.testTimeout,
+		StartPeriod:   s.startPeriod,
+		StartInterval: s.startInterval,
+		Retries:       s.retries + 1,
+	}
+}
+
+// NativeHealthConfig returns the health config that is applied to the container when the health check is run by Docker,
+// see WithNativeHealthCheck. It returns nil otherwise.
+func (s *HealthCheckStrategy) NativeHealthConfig() *container.HealthConfig {
+	if !s.native {
+		return nil
+	}
+
+	return s.HealthConfig()
+}
+
+func (s *HealthCheckStrategy) interval(elapsedTime time.Duration) time.Duration {
+	if s.startInterval > 0 && elapsedTime <= s.startPeriod {
+		return s.startInterval
+	}
+
+	return s.testInterval
+}
+
+func (s *HealthCheckStrategy) testTarget(target wait.StrategyTarget, attempt *HealthCheckAttempt) (success bool, err error) {
 	ctx, cancel := context.WithTimeout(context.Background(), s.testTimeout)
 	defer cancel()
 
-	success, err = s.test.Test(ctx, target)
+	attempt.StartedAt = time.Now()
+	attempt.ExitCode = -1
+
+	defer func() {
+		attempt.Duration = time.Since(attempt.StartedAt)
+	}()
+
+	if cmd, ok := s.test.(healthCheckTestCmd); ok {
+		success, err = cmd.run(ctx, target, attempt)
+	} else {
+		success, err = s.test.Test(ctx, target)
+	}
+
 	if errors.Is(err, context.DeadlineExceeded) {
+		attempt.TimedOut = true
+
 		return false, nil
 	}
 
 	return
 }
 
-// WaitUntilReady runs the health check test.
+// WaitUntilReady runs the health check test. If the health check is run by Docker, it waits until Docker reports that
+// the container is healthy.
+//
+// When the retries are exceeded, the error is a HealthCheckError that contains the failed attempts.
 func (s *HealthCheckStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	if s.NativeHealthConfig() != nil {
+		native := ForNativeHealthCheck()
+		native.expectedTest = s.healthTest
+
+		return native.WaitUntilReady(ctx, target)
+	}
+
 	pollInternal := time.Duration(0)
 	retry := 0
 
+	var attempts []HealthCheckAttempt
+
 	startTime := time.Now()
 
 	for {
@@ -81,7 +170,9 @@ func (s *HealthCheckStrategy) WaitUntilReady(ctx context.Context, target wait.St
 			return ctx.Err()
 
 		case <-time.After(pollInternal):
-			success, err := s.testTarget(target) // nolint: contextcheck
+			var attempt HealthCheckAttempt
+
+			success, err := s.testTarget(target, &attempt) // nolint: contextcheck
 			if err != nil {
 				return err
 			}
@@ -94,13 +185,17 @@ func (s *HealthCheckStrategy) WaitUntilReady(ctx context.Context, target wait.St
 
 			if elapsedTime > s.startPeriod {
 				retry++
+
+				attempt.Counted = true
 			}
 
+			attempts = append(attempts, attempt)
+
 			if retry > s.retries {
-				return fmt.Errorf("health check failed: %w", ErrMaxRetriesExceeded)
+				return &HealthCheckError{Attempts: attempts}
 			}
 
-			pollInternal = s.testInterval
+			pollInternal = s.interval(elapsedTime)
 		}
 	}
 }
@@ -120,8 +215,12 @@ func (f HealthCheckTestFunc) Test(ctx context.Context, target StrategyTarget) (s
 
 // ForHealthCheck creates a new health check with default arguments.
 func ForHealthCheck(f HealthCheckTestFunc) *HealthCheckStrategy {
+	return newHealthCheck(f)
+}
+
+func newHealthCheck(test HealthCheckTest) *HealthCheckStrategy {
 	return &HealthCheckStrategy{
-		test:         f,
+		test:         test,
 		retries:      defaultRetries,
 		testTimeout:  defaultTestTimeout,
 		testInterval: defaultTestInterval,
@@ -1,51 +1,92 @@
 package wait
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/testcontainers/testcontainers-go/wait"
 
 	"go.nhat.io/testcontainers-extra"
 )
 
-func healthCheckTestCmd(cmd []string) HealthCheckTestFunc {
-	return func(ctx context.Context, target wait.StrategyTarget) (success bool, err error) {
-		state, err := target.State(ctx)
+// maxHealthCheckOutput is the max length of the output of a health check command that is kept, same as Docker.
+const maxHealthCheckOutput = 4096
+
+// healthCheckTestCmd runs a command in the container, the container is healthy if the command exits with code 0.
+type healthCheckTestCmd []string
+
+// Test satisfies HealthCheckTest interface.
+func (cmd healthCheckTestCmd) Test(ctx context.Context, target wait.StrategyTarget) (success bool, err error) {
+	return cmd.run(ctx, target, &HealthCheckAttempt{})
+}
+
+// run runs the command and records its exit code and its output in the attempt.
+func (cmd healthCheckTestCmd) run(ctx context.Context, target wait.StrategyTarget, attempt *HealthCheckAttempt) (success bool, err error) {
+	state, err := target.State(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !isCmdTestable(state.Status) {
+		logs, err := target.Logs(ctx)
 		if err != nil {
-			return false, err
+			return false, fmt.Errorf("container is %s and unable to get logs: %w", state.Status, err)
 		}
 
-		if !isCmdTestable(state.Status) {
-			logs, err := target.Logs(ctx)
+		if logs != nil {
+			out, err := io.ReadAll(logs)
 			if err != nil {
-				return false, fmt.Errorf("container is %s and unable to get logs: %w", state.Status, err)
+				return false, fmt.Errorf("container is %s and unable to read logs: %w", state.Status, err)
 			}
 
-			if logs != nil {
-				out, err := io.ReadAll(logs)
-				if err != nil {
-					return false, fmt.Errorf("container is %s and unable to read logs: %w", state.Status, err)
-				}
+			return false, fmt.Errorf("container is %s, logs:\n%s", state.Status, string(out))
+		}
 
-				return false, fmt.Errorf("container is %s, logs:\n%s", state.Status, string(out))
-			}
+		return false, fmt.Errorf("container is %s and no logs", state.Status)
+	}
 
-			return false, fmt.Errorf("container is %s and no logs", state.Status)
-		}
+	if !state.Running {
+		return false, nil
+	}
 
-		if !state.Running {
-			return false, nil
-		}
+	code, out, err := target.Exec(ctx, cmd)
+	if err != nil {
+		return false, err
+	}
 
-		code, _, err := target.Exec(ctx, cmd)
-		if err != nil {
-			return false, err
-		}
+	attempt.ExitCode = code
+	attempt.Stdout, attempt.Stderr = readExecOutput(out)
 
-		return code == 0, nil
+	return code == 0, nil
+}
+
+// readExecOutput splits the multiplexed output of the command into stdout and stderr. The output that could not be
+// demultiplexed is kept as is.
+func readExecOutput(out io.Reader) (string, string) {
+	if out == nil {
+		return "", ""
+	}
+
+	data, _ := io.ReadAll(out) // nolint: errcheck
+
+	var stdout, stderr bytes.Buffer
+
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, bytes.NewReader(data)); err != nil {
+		return truncateOutput(string(data)), ""
 	}
+
+	return truncateOutput(stdout.String()), truncateOutput(stderr.String())
+}
+
+func truncateOutput(s string) string {
+	if len(s) > maxHealthCheckOutput {
+		return s[:maxHealthCheckOutput]
+	}
+
+	return s
 }
 
 // ForHealthCheckCmd checks by running a command in the container.
@@ -54,7 +95,21 @@ func ForHealthCheckCmd(cmd string, args ...string) *HealthCheckStrategy {
 	test = append(test, cmd)
 	test = append(test, args...)
 
-	return ForHealthCheck(healthCheckTestCmd(test))
+	return forHealthCheckTest(append([]string{"CMD"}, test...), test)
+}
+
+// ForHealthCheckShell checks by running a command with /bin/sh -c in the container.
+func ForHealthCheckShell(cmd string) *HealthCheckStrategy {
+	return forHealthCheckTest([]string{"CMD-SHELL", cmd}, append(defaultShell[:len(defaultShell):len(defaultShell)], cmd))
+}
+
+// forHealthCheckTest creates a health check that runs the command in the container. The test is the same command in
+// the format of the health config of Docker, see HealthCheckStrategy.HealthConfig.
+func forHealthCheckTest(test, cmd []string) *HealthCheckStrategy {
+	s := newHealthCheck(healthCheckTestCmd(cmd))
+	s.healthTest = test
+
+	return s
 }
 
 func isCmdTestable(status string) bool {
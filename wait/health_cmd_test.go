@@ -10,6 +10,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	waitmock "go.nhat.io/testcontainers-extra/mock/wait"
 	"go.nhat.io/testcontainers-extra/wait"
@@ -23,10 +24,11 @@ func TestForHealthCheckCmd(t *testing.T) {
 	runningState := &container.State{Status: "running", Running: true}
 
 	testCases := []struct {
-		scenario        string
-		mockTarget      waitmock.StrategyTargetMocker
-		expectedSuccess bool
-		expectedError   string
+		scenario          string
+		mockTarget        waitmock.StrategyTargetMocker
+		expectedSuccess   bool
+		expectedError     string
+		expectedExitCodes []int
 	}{
 		{
 			scenario: "unable to get state",
@@ -91,8 +93,8 @@ func TestForHealthCheckCmd(t *testing.T) {
 				t.On("State", isContext).
 					Return(restartingState, nil)
 			}),
-			expectedSuccess: false,
-			expectedError:   "health check failed: max retries exceeded",
+			expectedSuccess:   false,
+			expectedExitCodes: []int{-1},
 		},
 		{
 			scenario: "testable but exec error",
@@ -115,8 +117,8 @@ func TestForHealthCheckCmd(t *testing.T) {
 				t.On("Exec", isContext, []string{"test"}).
 					Return(1, nil, nil)
 			}),
-			expectedSuccess: false,
-			expectedError:   "health check failed: max retries exceeded",
+			expectedSuccess:   false,
+			expectedExitCodes: []int{1},
 		},
 		{
 			scenario: "testable and code ok",
@@ -142,15 +144,125 @@ func TestForHealthCheckCmd(t *testing.T) {
 
 			err := s.WaitUntilReady(context.Background(), tc.mockTarget(t))
 
-			if tc.expectedError == "" {
+			switch {
+			case tc.expectedExitCodes != nil:
+				assertHealthCheckError(t, err, tc.expectedExitCodes...)
+
+			case tc.expectedError == "":
 				assert.NoError(t, err)
-			} else {
+
+			default:
 				assert.EqualError(t, err, tc.expectedError)
 			}
 		})
 	}
 }
 
+func TestHealthCheckStrategy_HealthConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		strategy *wait.HealthCheckStrategy
+		expected *container.HealthConfig
+	}{
+		{
+			scenario: "test func",
+			strategy: wait.ForHealthCheck(func(context.Context, wait.StrategyTarget) (bool, error) {
+				return true, nil
+			}),
+		},
+		{
+			scenario: "cmd",
+			strategy: wait.ForHealthCheckCmd("pg_isready", "-U", "postgres").
+				WithRetries(2).
+				WithTestTimeout(time.Second).
+				WithTestInterval(5 * time.Second).
+				WithStartPeriod(30 * time.Second).
+				WithStartInterval(time.Second),
+			expected: &container.HealthConfig{
+				Test:          []string{"CMD", "pg_isready", "-U", "postgres"},
+				Interval:      5 * time.Second,
+				Timeout:       time.Second,
+				StartPeriod:   30 * time.Second,
+				StartInterval: time.Second,
+				Retries:       3,
+			},
+		},
+		{
+			scenario: "shell",
+			strategy: wait.ForHealthCheckShell("pg_isready || exit 1"),
+			expected: &container.HealthConfig{
+				Test:     []string{"CMD-SHELL", "pg_isready || exit 1"},
+				Interval: 5 * time.Second,
+				Timeout:  10 * time.Second,
+				Retries:  4,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.strategy.HealthConfig())
+			assert.Nil(t, tc.strategy.NativeHealthConfig())
+			assert.Equal(t, tc.expected, tc.strategy.WithNativeHealthCheck().NativeHealthConfig())
+		})
+	}
+}
+
+func TestHealthCheckFromConfig_HealthConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &container.HealthConfig{
+		Test:          []string{"CMD-SHELL", "pg_isready"},
+		Interval:      time.Second,
+		Timeout:       2 * time.Second,
+		StartPeriod:   3 * time.Second,
+		StartInterval: 500 * time.Millisecond,
+		Retries:       5,
+	}
+
+	s, err := wait.HealthCheckFromConfig(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, cfg, s.HealthConfig())
+}
+
+func TestHealthCheckFromConfig_DefaultStartInterval(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario              string
+		startPeriod           time.Duration
+		expectedStartInterval time.Duration
+	}{
+		{
+			scenario: "no start period",
+		},
+		{
+			scenario:              "start period",
+			startPeriod:           time.Minute,
+			expectedStartInterval: 5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := wait.HealthCheckFromConfig(&container.HealthConfig{
+				Test:        []string{"CMD-SHELL", "pg_isready"},
+				StartPeriod: tc.startPeriod,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStartInterval, s.HealthConfig().StartInterval)
+		})
+	}
+}
+
 var isContext = mock.MatchedBy(func(ctx interface{}) bool {
 	_, is := ctx.(context.Context)
 
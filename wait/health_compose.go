@@ -0,0 +1,154 @@
+package wait
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeHealthCheck is the healthcheck block of docker-compose, which could be unmarshalled from YAML or JSON. The
+// durations are in the format of docker-compose, such as "1m30s" or "500ms".
+//
+// For example:
+//
+//	healthcheck:
+//	  test: ["CMD-SHELL", "pg_isready -U postgres"]
+//	  interval: 10s
+//	  timeout: 5s
+//	  retries: 5
+//	  start_period: 30s
+//
+// is
+//
+//	wait.ComposeHealthCheck{
+//		Test:        wait.ComposeHealthCheckTest{"CMD-SHELL", "pg_isready -U postgres"},
+//		Interval:    "10s",
+//		Timeout:     "5s",
+//		Retries:     5,
+//		StartPeriod: "30s",
+//	}
+type ComposeHealthCheck struct {
+	Test          ComposeHealthCheckTest `json:"test,omitempty" yaml:"test,omitempty"`
+	Interval      string                 `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout       string                 `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retries       int                    `json:"retries,omitempty" yaml:"retries,omitempty"`
+	StartPeriod   string                 `json:"start_period,omitempty" yaml:"start_period,omitempty"`
+	StartInterval string                 `json:"start_interval,omitempty" yaml:"start_interval,omitempty"`
+	Disable       bool                   `json:"disable,omitempty" yaml:"disable,omitempty"`
+}
+
+// HealthConfig converts the health check to the health config of Docker.
+func (c ComposeHealthCheck) HealthConfig() (*container.HealthConfig, error) {
+	if c.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}, nil
+	}
+
+	cfg := &container.HealthConfig{
+		Test:    c.Test.normalize(),
+		Retries: c.Retries,
+	}
+
+	durations := []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{name: "interval", value: c.Interval, dest: &cfg.Interval},
+		{name: "timeout", value: c.Timeout, dest: &cfg.Timeout},
+		{name: "start_period", value: c.StartPeriod, dest: &cfg.StartPeriod},
+		{name: "start_interval", value: c.StartInterval, dest: &cfg.StartInterval},
+	}
+
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", d.name, d.value, err)
+		}
+
+		*d.dest = v
+	}
+
+	return cfg, nil
+}
+
+// ComposeHealthCheckTest is the test of the healthcheck block of docker-compose. It is either ["CMD", args...],
+// ["CMD-SHELL", command], ["NONE"], or a command in the shell form, which is the same as a plain string in
+// docker-compose. It could be unmarshalled from a string or an array of strings, in YAML or JSON.
+type ComposeHealthCheckTest []string
+
+// UnmarshalJSON satisfies json.Unmarshaler.
+func (t *ComposeHealthCheckTest) UnmarshalJSON(data []byte) error {
+	var s string
+
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = ComposeHealthCheckTest{s}
+
+		return nil
+	}
+
+	var test []string
+
+	if err := json.Unmarshal(data, &test); err != nil {
+		return fmt.Errorf("health check test must be a string or an array of strings: %w", err)
+	}
+
+	*t = test
+
+	return nil
+}
+
+// UnmarshalYAML satisfies yaml.Unmarshaler.
+func (t *ComposeHealthCheckTest) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var s string
+
+		if err := value.Decode(&s); err != nil {
+			return fmt.Errorf("health check test must be a string or an array of strings: %w", err)
+		}
+
+		*t = ComposeHealthCheckTest{s}
+
+		return nil
+	}
+
+	var test []string
+
+	if err := value.Decode(&test); err != nil {
+		return fmt.Errorf("health check test must be a string or an array of strings: %w", err)
+	}
+
+	*t = test
+
+	return nil
+}
+
+func (t ComposeHealthCheckTest) normalize() []string {
+	if len(t) != 1 {
+		return t
+	}
+
+	switch t[0] {
+	case "NONE", "CMD", "CMD-SHELL":
+		return t
+	}
+
+	return []string{"CMD-SHELL", t[0]}
+}
+
+// ForComposeHealthCheck creates a health check from the healthcheck block of docker-compose, which runs in the test
+// process. See HealthCheckFromConfig.
+func ForComposeHealthCheck(c ComposeHealthCheck) (*HealthCheckStrategy, error) {
+	cfg, err := c.HealthConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return HealthCheckFromConfig(cfg)
+}
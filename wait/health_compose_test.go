@@ -0,0 +1,292 @@
+package wait_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gopkg.in/yaml.v3"
+
+	waitmock "go.nhat.io/testcontainers-extra/mock/wait"
+	"go.nhat.io/testcontainers-extra/wait"
+)
+
+func TestComposeHealthCheck_HealthConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario       string
+		healthCheck    wait.ComposeHealthCheck
+		expectedConfig *container.HealthConfig
+		expectedError  string
+	}{
+		{
+			scenario: "cmd",
+			healthCheck: wait.ComposeHealthCheck{
+				Test:          wait.ComposeHealthCheckTest{"CMD", "pg_isready", "-U", "postgres"},
+				Interval:      "1m30s",
+				Timeout:       "10s",
+				Retries:       3,
+				StartPeriod:   "40s",
+				StartInterval: "500ms",
+			},
+			expectedConfig: &container.HealthConfig{
+				Test:          []string{"CMD", "pg_isready", "-U", "postgres"},
+				Interval:      90 * time.Second,
+				Timeout:       10 * time.Second,
+				Retries:       3,
+				StartPeriod:   40 * time.Second,
+				StartInterval: 500 * time.Millisecond,
+			},
+		},
+		{
+			scenario: "cmd-shell",
+			healthCheck: wait.ComposeHealthCheck{
+				Test: wait.ComposeHealthCheckTest{"CMD-SHELL", "pg_isready && psql -c 'select 1'"},
+			},
+			expectedConfig: &container.HealthConfig{
+				Test: []string{"CMD-SHELL", "pg_isready && psql -c 'select 1'"},
+			},
+		},
+		{
+			scenario: "shell form",
+			healthCheck: wait.ComposeHealthCheck{
+				Test: wait.ComposeHealthCheckTest{"pg_isready && psql -c 'select 1'"},
+			},
+			expectedConfig: &container.HealthConfig{
+				Test: []string{"CMD-SHELL", "pg_isready && psql -c 'select 1'"},
+			},
+		},
+		{
+			scenario: "none",
+			healthCheck: wait.ComposeHealthCheck{
+				Test: wait.ComposeHealthCheckTest{"NONE"},
+			},
+			expectedConfig: &container.HealthConfig{
+				Test: []string{"NONE"},
+			},
+		},
+		{
+			scenario: "disable",
+			healthCheck: wait.ComposeHealthCheck{
+				Test:    wait.ComposeHealthCheckTest{"CMD", "true"},
+				Disable: true,
+			},
+			expectedConfig: &container.HealthConfig{
+				Test: []string{"NONE"},
+			},
+		},
+		{
+			scenario: "invalid duration",
+			healthCheck: wait.ComposeHealthCheck{
+				Test:        wait.ComposeHealthCheckTest{"CMD", "true"},
+				StartPeriod: "forever",
+			},
+			expectedError: `invalid start_period "forever": time: invalid duration "forever"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tc.healthCheck.HealthConfig()
+
+			assert.Equal(t, tc.expectedConfig, actual)
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestComposeHealthCheck_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		data          string
+		expected      wait.ComposeHealthCheck
+		expectedError string
+	}{
+		{
+			scenario: "string",
+			data:     `{"test": "pg_isready", "interval": "10s", "retries": 5, "start_period": "30s", "start_interval": "1s"}`,
+			expected: wait.ComposeHealthCheck{
+				Test:          wait.ComposeHealthCheckTest{"pg_isready"},
+				Interval:      "10s",
+				Retries:       5,
+				StartPeriod:   "30s",
+				StartInterval: "1s",
+			},
+		},
+		{
+			scenario: "array",
+			data:     `{"test": ["CMD", "pg_isready"], "timeout": "5s"}`,
+			expected: wait.ComposeHealthCheck{
+				Test:    wait.ComposeHealthCheckTest{"CMD", "pg_isready"},
+				Timeout: "5s",
+			},
+		},
+		{
+			scenario:      "invalid",
+			data:          `{"test": 42}`,
+			expectedError: `health check test must be a string or an array of strings: json: cannot unmarshal number into Go value of type []string`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var actual wait.ComposeHealthCheck
+
+			err := json.Unmarshal([]byte(tc.data), &actual)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestComposeHealthCheck_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		data          string
+		expected      wait.ComposeHealthCheck
+		expectedError string
+	}{
+		{
+			scenario: "string",
+			data: `
+healthcheck:
+  test: pg_isready -U postgres
+  interval: 10s
+  retries: 5
+  start_period: 30s
+  start_interval: 1s
+`,
+			expected: wait.ComposeHealthCheck{
+				Test:          wait.ComposeHealthCheckTest{"pg_isready -U postgres"},
+				Interval:      "10s",
+				Retries:       5,
+				StartPeriod:   "30s",
+				StartInterval: "1s",
+			},
+		},
+		{
+			scenario: "array",
+			data: `
+healthcheck:
+  test: ["CMD-SHELL", "pg_isready -U postgres"]
+  timeout: 5s
+`,
+			expected: wait.ComposeHealthCheck{
+				Test:    wait.ComposeHealthCheckTest{"CMD-SHELL", "pg_isready -U postgres"},
+				Timeout: "5s",
+			},
+		},
+		{
+			scenario: "block sequence",
+			data: `
+healthcheck:
+  test:
+    - CMD
+    - pg_isready
+  disable: true
+`,
+			expected: wait.ComposeHealthCheck{
+				Test:    wait.ComposeHealthCheckTest{"CMD", "pg_isready"},
+				Disable: true,
+			},
+		},
+		{
+			scenario: "invalid",
+			data: `
+healthcheck:
+  test:
+    cmd: pg_isready
+`,
+			expectedError: "health check test must be a string or an array of strings: yaml: unmarshal errors:\n  line 4: cannot unmarshal !!map into []string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var actual struct {
+				HealthCheck wait.ComposeHealthCheck `yaml:"healthcheck"`
+			}
+
+			err := yaml.Unmarshal([]byte(tc.data), &actual)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual.HealthCheck)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestForComposeHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	_, err := wait.ForComposeHealthCheck(wait.ComposeHealthCheck{Interval: "soon"})
+	require.EqualError(t, err, `invalid interval "soon": time: invalid duration "soon"`)
+
+	_, err = wait.ForComposeHealthCheck(wait.ComposeHealthCheck{Disable: true})
+	require.ErrorIs(t, err, wait.ErrNoHealthCheck)
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("State", isContext).
+			Return(&container.State{Status: "running", Running: true}, nil)
+
+		t.On("Exec", isContext, []string{"/bin/sh", "-c", "pg_isready && true"}).
+			Return(1, nil, nil).Once()
+
+		t.On("Exec", isContext, []string{"/bin/sh", "-c", "pg_isready && true"}).
+			Return(0, nil, nil).Once()
+	})(t)
+
+	s, err := wait.ForComposeHealthCheck(wait.ComposeHealthCheck{
+		Test:     wait.ComposeHealthCheckTest{"pg_isready && true"},
+		Interval: "1ms",
+	})
+	require.NoError(t, err)
+
+	assert.NoError(t, s.WaitUntilReady(context.Background(), target))
+}
+
+func TestForHealthCheckShell(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("State", isContext).
+			Return(&container.State{Status: "running", Running: true}, nil)
+
+		t.On("Exec", isContext, []string{"/bin/sh", "-c", "pg_isready || exit 1"}).
+			Return(0, nil, nil).Once()
+	})(t)
+
+	err := wait.ForHealthCheckShell("pg_isready || exit 1").
+		WaitUntilReady(context.Background(), target)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,151 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// The default values of the health check of Docker, which are used when the config does not set them.
+const (
+	defaultConfigRetries      = 3
+	defaultConfigTestTimeout  = 30 * time.Second
+	defaultConfigTestInterval = 30 * time.Second
+	// Docker probes every 5s during the start period if the start interval is not set.
+	defaultConfigStartInterval = 5 * time.Second
+)
+
+var (
+	_ wait.Strategy = (*ImageHealthCheckStrategy)(nil)
+
+	defaultShell = []string{"/bin/sh", "-c"}
+)
+
+// HealthCheckFromConfig creates a health check that runs the test of the config in the container, with the same
+// interval, timeout, retries, start period and start interval. The zero values fall back to the defaults of Docker, the
+// start interval is 5s when there is a start period.
+//
+// Docker considers the container unhealthy after the given number of consecutive failures, while the health check
+// fails when the failures exceed the given number of retries, so the retries of the health check are one less.
+//
+// The test is either ["CMD", args...] or ["CMD-SHELL", command], the shell form runs with /bin/sh -c. It returns
+// ErrNoHealthCheck if there is no test or the test is ["NONE"].
+func HealthCheckFromConfig(cfg *container.HealthConfig) (*HealthCheckStrategy, error) {
+	return healthCheckFromConfig(cfg, nil)
+}
+
+func healthCheckFromConfig(cfg *container.HealthConfig, shell []string) (*HealthCheckStrategy, error) {
+	if cfg == nil {
+		return nil, ErrNoHealthCheck
+	}
+
+	cmd, err := healthCheckConfigCmd(cfg.Test, shell)
+	if err != nil {
+		return nil, err
+	}
+
+	s := forHealthCheckTest(cfg.Test, cmd).
+		WithRetries(defaultConfigRetries - 1).
+		WithTestTimeout(defaultConfigTestTimeout).
+		WithTestInterval(defaultConfigTestInterval).
+		WithStartPeriod(cfg.StartPeriod).
+		WithStartInterval(cfg.StartInterval)
+
+	if cfg.Retries > 0 {
+		s.WithRetries(cfg.Retries - 1)
+	}
+
+	if cfg.Timeout > 0 {
+		s.WithTestTimeout(cfg.Timeout)
+	}
+
+	if cfg.Interval > 0 {
+		s.WithTestInterval(cfg.Interval)
+	}
+
+	if cfg.StartPeriod > 0 && cfg.StartInterval <= 0 {
+		s.WithStartInterval(defaultConfigStartInterval)
+	}
+
+	return s, nil
+}
+
+func healthCheckConfigCmd(test, shell []string) ([]string, error) {
+	if len(test) == 0 {
+		return nil, ErrNoHealthCheck
+	}
+
+	switch test[0] {
+	case "NONE":
+		return nil, ErrNoHealthCheck
+
+	case "CMD":
+		if len(test) < 2 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHealthCheckTest, test)
+		}
+
+		return test[1:], nil
+
+	case "CMD-SHELL":
+		if len(test) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHealthCheckTest, test)
+		}
+
+		if len(shell) == 0 {
+			shell = defaultShell
+		}
+
+		cmd := make([]string, 0, len(shell)+1)
+		cmd = append(cmd, shell...)
+
+		return append(cmd, test[1]), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrInvalidHealthCheckTest, test)
+}
+
+// ImageHealthCheckStrategy is a strategy that runs the health check of the container in the test process. The health
+// check is read from the config of the container, which inherits the HEALTHCHECK of the image.
+type ImageHealthCheckStrategy struct {
+	modifiers []func(s *HealthCheckStrategy)
+}
+
+// WithHealthCheckModifier modifies the health check after it is read from the config of the container, for example to
+// poll more often than the image does.
+func (s *ImageHealthCheckStrategy) WithHealthCheckModifier(modify func(s *HealthCheckStrategy)) *ImageHealthCheckStrategy {
+	s.modifiers = append(s.modifiers, modify)
+
+	return s
+}
+
+// WaitUntilReady reads the health check of the container and runs it, see HealthCheckFromConfig.
+func (s *ImageHealthCheckStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	info, err := target.Inspect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if info.Config == nil {
+		return fmt.Errorf("health check failed: %w", ErrNoHealthCheck)
+	}
+
+	hc, err := healthCheckFromConfig(info.Config.Healthcheck, info.Config.Shell)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	for _, modify := range s.modifiers {
+		modify(hc)
+	}
+
+	return hc.WaitUntilReady(ctx, target)
+}
+
+// ForImageHealthCheck runs the health check of the image, or the health config of the container, in the test process
+// instead of relying on the health status that Docker reports. See ForNativeHealthCheck for the latter.
+func ForImageHealthCheck() *ImageHealthCheckStrategy {
+	return &ImageHealthCheckStrategy{}
+}
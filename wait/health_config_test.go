@@ -0,0 +1,218 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	waitmock "go.nhat.io/testcontainers-extra/mock/wait"
+	"go.nhat.io/testcontainers-extra/wait"
+)
+
+func TestHealthCheckFromConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		config        *container.HealthConfig
+		expectedError string
+	}{
+		{
+			scenario:      "nil",
+			expectedError: `no health check configured`,
+		},
+		{
+			scenario:      "no test",
+			config:        &container.HealthConfig{},
+			expectedError: `no health check configured`,
+		},
+		{
+			scenario:      "none",
+			config:        &container.HealthConfig{Test: []string{"NONE"}},
+			expectedError: `no health check configured`,
+		},
+		{
+			scenario:      "cmd without args",
+			config:        &container.HealthConfig{Test: []string{"CMD"}},
+			expectedError: `invalid health check test: ["CMD"]`,
+		},
+		{
+			scenario:      "cmd-shell with many args",
+			config:        &container.HealthConfig{Test: []string{"CMD-SHELL", "pg_isready", "-U"}},
+			expectedError: `invalid health check test: ["CMD-SHELL" "pg_isready" "-U"]`,
+		},
+		{
+			scenario:      "unknown",
+			config:        &container.HealthConfig{Test: []string{"pg_isready"}},
+			expectedError: `invalid health check test: ["pg_isready"]`,
+		},
+		{
+			scenario: "cmd",
+			config:   &container.HealthConfig{Test: []string{"CMD", "pg_isready"}},
+		},
+		{
+			scenario: "cmd-shell",
+			config:   &container.HealthConfig{Test: []string{"CMD-SHELL", "pg_isready"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := wait.HealthCheckFromConfig(tc.config)
+
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				assert.NotNil(t, s)
+			} else {
+				assert.Nil(t, s)
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestHealthCheckFromConfig_ErrNoHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	_, err := wait.HealthCheckFromConfig(&container.HealthConfig{Test: []string{"NONE"}})
+
+	assert.ErrorIs(t, err, wait.ErrNoHealthCheck)
+}
+
+func TestForImageHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	runningState := &container.State{Status: "running", Running: true}
+
+	inspectResponse := func(cfg *container.Config) *container.InspectResponse {
+		return &container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{State: runningState},
+			Config:            cfg,
+		}
+	}
+
+	healthConfig := &container.HealthConfig{
+		Test:     []string{"CMD-SHELL", "pg_isready"},
+		Interval: time.Millisecond,
+		Timeout:  time.Second,
+		Retries:  2,
+	}
+
+	testCases := []struct {
+		scenario          string
+		mockTarget        waitmock.StrategyTargetMocker
+		expectedError     string
+		expectedExitCodes []int
+	}{
+		{
+			scenario: "unable to inspect",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nil, errors.New("inspect error"))
+			}),
+			expectedError: `inspect error`,
+		},
+		{
+			scenario: "no config",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(inspectResponse(nil), nil)
+			}),
+			expectedError: `health check failed: no health check configured`,
+		},
+		{
+			scenario: "no health check",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(inspectResponse(&container.Config{}), nil)
+			}),
+			expectedError: `health check failed: no health check configured`,
+		},
+		{
+			scenario: "max retries exceeded",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(inspectResponse(&container.Config{Healthcheck: healthConfig}), nil)
+
+				t.On("State", isContext).
+					Return(runningState, nil)
+
+				t.On("Exec", isContext, []string{"/bin/sh", "-c", "pg_isready"}).
+					Return(1, nil, nil).Twice()
+			}),
+			expectedExitCodes: []int{1, 1},
+		},
+		{
+			scenario: "success with the shell of the container",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(inspectResponse(&container.Config{Healthcheck: healthConfig, Shell: []string{"/bin/bash", "-c"}}), nil)
+
+				t.On("State", isContext).
+					Return(runningState, nil)
+
+				t.On("Exec", isContext, []string{"/bin/bash", "-c", "pg_isready"}).
+					Return(1, nil, nil).Once()
+
+				t.On("Exec", isContext, []string{"/bin/bash", "-c", "pg_isready"}).
+					Return(0, nil, nil).Once()
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			err := wait.ForImageHealthCheck().
+				WaitUntilReady(context.Background(), tc.mockTarget(t))
+
+			switch {
+			case tc.expectedExitCodes != nil:
+				assertHealthCheckError(t, err, tc.expectedExitCodes...)
+
+			case tc.expectedError == "":
+				assert.NoError(t, err)
+
+			default:
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestForImageHealthCheck_WithHealthCheckModifier(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(&container.InspectResponse{
+				Config: &container.Config{Healthcheck: &container.HealthConfig{
+					Test:     []string{"CMD", "pg_isready"},
+					Interval: time.Hour,
+					Retries:  1,
+				}},
+			}, nil)
+
+		t.On("State", isContext).
+			Return(&container.State{Status: "running", Running: true}, nil)
+
+		t.On("Exec", isContext, []string{"pg_isready"}).
+			Return(1, nil, nil).Times(3)
+	})(t)
+
+	err := wait.ForImageHealthCheck().
+		WithHealthCheckModifier(func(s *wait.HealthCheckStrategy) {
+			s.WithTestInterval(time.Millisecond).WithRetries(2)
+		}).
+		WaitUntilReady(context.Background(), target)
+
+	assert.ErrorIs(t, err, wait.ErrMaxRetriesExceeded)
+}
@@ -0,0 +1,89 @@
+package wait
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HealthCheckAttempt is a failed attempt of the health check.
+type HealthCheckAttempt struct {
+	// StartedAt is the time when the test started.
+	StartedAt time.Time
+	// Duration is how long the test took.
+	Duration time.Duration
+	// ExitCode is the exit code of the command, or -1 if the test did not run a command.
+	ExitCode int
+	// Stdout is the standard output of the command, truncated to 4096 bytes.
+	Stdout string
+	// Stderr is the standard error of the command, truncated to 4096 bytes.
+	Stderr string
+	// TimedOut tells whether the test exceeded the test timeout.
+	TimedOut bool
+	// Counted tells whether the attempt was counted against the retries, the attempts in the start period are not.
+	Counted bool
+}
+
+// String returns a short description of the attempt.
+func (a HealthCheckAttempt) String() string {
+	var sb strings.Builder
+
+	_, _ = fmt.Fprintf(&sb, "%s (%s", a.StartedAt.Format(time.RFC3339Nano), a.Duration)
+
+	if !a.Counted {
+		sb.WriteString(", in start period")
+	}
+
+	sb.WriteString(")")
+
+	switch {
+	case a.TimedOut:
+		sb.WriteString(": timed out")
+
+	case a.ExitCode >= 0:
+		_, _ = fmt.Fprintf(&sb, ": exit code %d", a.ExitCode)
+	}
+
+	writeOutput(&sb, "stdout", a.Stdout)
+	writeOutput(&sb, "stderr", a.Stderr)
+
+	return sb.String()
+}
+
+func writeOutput(sb *strings.Builder, name, out string) {
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return
+	}
+
+	_, _ = fmt.Fprintf(sb, "\n  %s: %s", name, strings.ReplaceAll(out, "\n", "\n    "))
+}
+
+// HealthCheckError is the error when the health check exceeds the max retries. It matches ErrMaxRetriesExceeded.
+type HealthCheckError struct {
+	// Attempts are the failed attempts, in order.
+	Attempts []HealthCheckAttempt
+}
+
+// Error satisfies error interface.
+func (e *HealthCheckError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("health check failed: ")
+	sb.WriteString(ErrMaxRetriesExceeded.Error())
+
+	if len(e.Attempts) > 0 {
+		_, _ = fmt.Fprintf(&sb, "\nattempts (%d):", len(e.Attempts))
+	}
+
+	for i, a := range e.Attempts {
+		_, _ = fmt.Fprintf(&sb, "\n%d. %s", i+1, a.String())
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns ErrMaxRetriesExceeded.
+func (e *HealthCheckError) Unwrap() error {
+	return ErrMaxRetriesExceeded
+}
@@ -0,0 +1,153 @@
+package wait_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	waitmock "go.nhat.io/testcontainers-extra/mock/wait"
+	"go.nhat.io/testcontainers-extra/wait"
+)
+
+func TestHealthCheckError(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err := &wait.HealthCheckError{Attempts: []wait.HealthCheckAttempt{
+		{StartedAt: startedAt, Duration: 5 * time.Millisecond, ExitCode: -1},
+		{StartedAt: startedAt.Add(time.Second), Duration: time.Second, ExitCode: -1, TimedOut: true, Counted: true},
+		{
+			StartedAt: startedAt.Add(2 * time.Second),
+			Duration:  20 * time.Millisecond,
+			ExitCode:  2,
+			Stdout:    "line 1\nline 2\n",
+			Stderr:    "no response\n",
+			Counted:   true,
+		},
+	}}
+
+	expected := "health check failed: max retries exceeded\n" +
+		"attempts (3):\n" +
+		"1. 2020-01-02T03:04:05Z (5ms, in start period)\n" +
+		"2. 2020-01-02T03:04:06Z (1s): timed out\n" +
+		"3. 2020-01-02T03:04:07Z (20ms): exit code 2\n" +
+		"  stdout: line 1\n" +
+		"    line 2\n" +
+		"  stderr: no response"
+
+	assert.EqualError(t, err, expected)
+	assert.ErrorIs(t, fmt.Errorf("wrapped: %w", err), wait.ErrMaxRetriesExceeded)
+}
+
+func TestHealthCheckStrategy_AttemptHistory(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	_, err := stdcopy.NewStdWriter(&out, stdcopy.Stdout).Write([]byte("accepting connections\n"))
+	require.NoError(t, err)
+
+	_, err = stdcopy.NewStdWriter(&out, stdcopy.Stderr).Write([]byte("role does not exist\n"))
+	require.NoError(t, err)
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("State", isContext).
+			Return(&container.State{Status: "running", Running: true}, nil)
+
+		t.On("Exec", isContext, []string{"pg_isready"}).
+			Return(2, bytes.NewReader(out.Bytes()), nil).Once()
+
+		t.On("Exec", isContext, []string{"pg_isready"}).
+			Return(3, strings.NewReader("not multiplexed"), nil).Once()
+	})(t)
+
+	startTime := time.Now()
+
+	err = wait.ForHealthCheckCmd("pg_isready").
+		WithRetries(1).
+		WithTestInterval(time.Millisecond).
+		WaitUntilReady(context.Background(), target)
+
+	var hcErr *wait.HealthCheckError
+
+	require.ErrorAs(t, err, &hcErr)
+	require.Len(t, hcErr.Attempts, 2)
+
+	first := hcErr.Attempts[0]
+
+	assert.Equal(t, 2, first.ExitCode)
+	assert.Equal(t, "accepting connections\n", first.Stdout)
+	assert.Equal(t, "role does not exist\n", first.Stderr)
+	assert.True(t, first.Counted)
+	assert.False(t, first.TimedOut)
+	assert.False(t, first.StartedAt.Before(startTime))
+	assert.Positive(t, first.Duration)
+
+	second := hcErr.Attempts[1]
+
+	assert.Equal(t, 3, second.ExitCode)
+	assert.Equal(t, "not multiplexed", second.Stdout)
+	assert.Empty(t, second.Stderr)
+	assert.True(t, second.StartedAt.After(first.StartedAt))
+}
+
+// nolint: paralleltest
+func TestHealthCheckStrategy_AttemptHistory_StartPeriodAndTimeout(t *testing.T) {
+	called := 0
+
+	err := wait.ForHealthCheck(func(ctx context.Context, _ wait.StrategyTarget) (success bool, err error) {
+		called++
+
+		if called == 1 {
+			return false, nil
+		}
+
+		<-ctx.Done()
+
+		return false, ctx.Err()
+	}).
+		WithRetries(0).
+		WithTestTimeout(5*time.Millisecond).
+		WithTestInterval(30*time.Millisecond).
+		WithStartPeriod(20*time.Millisecond).
+		WaitUntilReady(context.Background(), nil)
+
+	var hcErr *wait.HealthCheckError
+
+	require.ErrorAs(t, err, &hcErr)
+	require.Len(t, hcErr.Attempts, 2)
+
+	assert.False(t, hcErr.Attempts[0].Counted, "first attempt is in the start period")
+	assert.False(t, hcErr.Attempts[0].TimedOut)
+	assert.True(t, hcErr.Attempts[1].Counted)
+	assert.True(t, hcErr.Attempts[1].TimedOut)
+	assert.Equal(t, -1, hcErr.Attempts[1].ExitCode)
+}
+
+// assertHealthCheckError asserts that the health check exceeded the max retries with the attempts of the exit codes.
+func assertHealthCheckError(t *testing.T, err error, expectedExitCodes ...int) {
+	t.Helper()
+
+	var hcErr *wait.HealthCheckError
+
+	require.ErrorAs(t, err, &hcErr)
+	assert.ErrorIs(t, err, wait.ErrMaxRetriesExceeded)
+
+	exitCodes := make([]int, 0, len(hcErr.Attempts))
+
+	for _, a := range hcErr.Attempts {
+		exitCodes = append(exitCodes, a.ExitCode)
+	}
+
+	assert.Equal(t, expectedExitCodes, exitCodes)
+	assert.True(t, strings.HasPrefix(err.Error(), fmt.Sprintf("health check failed: max retries exceeded\nattempts (%d):\n", len(expectedExitCodes))))
+}
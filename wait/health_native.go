@@ -0,0 +1,159 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	defaultNativePollInterval = 100 * time.Millisecond
+	defaultNativeLogEntries   = 5
+)
+
+var _ wait.Strategy = (*NativeHealthCheckStrategy)(nil)
+
+// NativeHealthCheckStrategy is a strategy that follows the status of the HEALTHCHECK of the container, which is run by
+// Docker.
+type NativeHealthCheckStrategy struct {
+	pollInterval time.Duration
+	logEntries   int
+	// expectedTest is the test that the container must have, it is not checked if empty.
+	expectedTest []string
+}
+
+// WithPollInterval sets the interval between inspecting the container.
+func (s *NativeHealthCheckStrategy) WithPollInterval(interval time.Duration) *NativeHealthCheckStrategy {
+	s.pollInterval = interval
+
+	return s
+}
+
+// WithLogEntries sets the number of the last health check results in the error when the container is unhealthy.
+func (s *NativeHealthCheckStrategy) WithLogEntries(n int) *NativeHealthCheckStrategy {
+	s.logEntries = n
+
+	return s
+}
+
+// WaitUntilReady waits until the container is healthy. It fails immediately if the container is unhealthy, stops, or
+// has no health check.
+func (s *NativeHealthCheckStrategy) WaitUntilReady(ctx context.Context, target wait.StrategyTarget) error {
+	pollInterval := time.Duration(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-time.After(pollInterval):
+			healthy, err := s.checkHealth(ctx, target)
+			if err != nil || healthy {
+				return err
+			}
+
+			pollInterval = s.pollInterval
+		}
+	}
+}
+
+func (s *NativeHealthCheckStrategy) checkHealth(ctx context.Context, target wait.StrategyTarget) (bool, error) {
+	info, err := target.Inspect(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !hasNativeHealthCheck(info.Config) {
+		return false, fmt.Errorf("health check failed: %w", ErrNoHealthCheck)
+	}
+
+	if len(s.expectedTest) > 0 && !slices.Equal(info.Config.Healthcheck.Test, s.expectedTest) {
+		return false, fmt.Errorf("health check failed: %w: expected %q, got %q", ErrHealthCheckMismatch, s.expectedTest, info.Config.Healthcheck.Test)
+	}
+
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return false, nil
+	}
+
+	if !isCmdTestable(info.State.Status) {
+		return false, fmt.Errorf("health check failed: container is %s", info.State.Status)
+	}
+
+	if info.State.Health == nil {
+		return false, nil
+	}
+
+	switch info.State.Health.Status {
+	case container.Healthy:
+		return true, nil
+
+	case container.Unhealthy:
+		return false, &UnhealthyError{Logs: lastHealthLogs(info.State.Health.Log, s.logEntries)}
+	}
+
+	return false, nil
+}
+
+func hasNativeHealthCheck(cfg *container.Config) bool {
+	if cfg == nil || cfg.Healthcheck == nil || len(cfg.Healthcheck.Test) == 0 {
+		return false
+	}
+
+	return cfg.Healthcheck.Test[0] != "NONE"
+}
+
+func lastHealthLogs(logs []*container.HealthcheckResult, n int) []*container.HealthcheckResult {
+	if n > 0 && len(logs) > n {
+		return logs[len(logs)-n:]
+	}
+
+	return logs
+}
+
+// UnhealthyError is the error when Docker reports that the container is unhealthy.
+type UnhealthyError struct {
+	// Logs are the last results of the health check.
+	Logs []*container.HealthcheckResult
+}
+
+// Error satisfies error interface.
+func (e *UnhealthyError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("health check failed: ")
+	sb.WriteString(ErrUnhealthy.Error())
+
+	if len(e.Logs) > 0 {
+		sb.WriteString("\nlast health checks:")
+	}
+
+	for _, l := range e.Logs {
+		_, _ = fmt.Fprintf(&sb, "\n- %s (exit code %d)", l.Start.Format(time.RFC3339), l.ExitCode)
+
+		if out := strings.TrimSpace(l.Output); out != "" {
+			sb.WriteString(": ")
+			sb.WriteString(out)
+		}
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns ErrUnhealthy.
+func (e *UnhealthyError) Unwrap() error {
+	return ErrUnhealthy
+}
+
+// ForNativeHealthCheck waits until Docker reports that the container is healthy, following the HEALTHCHECK of the image
+// or the health config of the container. The container must have a health check.
+func ForNativeHealthCheck() *NativeHealthCheckStrategy {
+	return &NativeHealthCheckStrategy{
+		pollInterval: defaultNativePollInterval,
+		logEntries:   defaultNativeLogEntries,
+	}
+}
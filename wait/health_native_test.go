@@ -0,0 +1,232 @@
+package wait_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	waitmock "go.nhat.io/testcontainers-extra/mock/wait"
+	"go.nhat.io/testcontainers-extra/wait"
+)
+
+func nativeInspectResponse(test []string, state *container.State) *container.InspectResponse {
+	info := &container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{State: state},
+		Config:            &container.Config{},
+	}
+
+	if test != nil {
+		info.Config.Healthcheck = &container.HealthConfig{Test: test}
+	}
+
+	return info
+}
+
+func runningHealthState(status string, logs ...*container.HealthcheckResult) *container.State {
+	return &container.State{
+		Status:  "running",
+		Running: true,
+		Health:  &container.Health{Status: status, Log: logs},
+	}
+}
+
+func TestForNativeHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	healthCheck := []string{"CMD-SHELL", "pg_isready"}
+	startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		scenario      string
+		mockTarget    waitmock.StrategyTargetMocker
+		expectedError string
+	}{
+		{
+			scenario: "unable to inspect",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nil, errors.New("inspect error"))
+			}),
+			expectedError: `inspect error`,
+		},
+		{
+			scenario: "no health check",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(nil, &container.State{Status: "running", Running: true}), nil)
+			}),
+			expectedError: `health check failed: no health check configured`,
+		},
+		{
+			scenario: "health check is disabled",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse([]string{"NONE"}, &container.State{Status: "running", Running: true}), nil)
+			}),
+			expectedError: `health check failed: no health check configured`,
+		},
+		{
+			scenario: "container exited",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(healthCheck, &container.State{Status: "exited"}), nil)
+			}),
+			expectedError: `health check failed: container is exited`,
+		},
+		{
+			scenario: "unhealthy",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(healthCheck, runningHealthState(container.Unhealthy,
+						&container.HealthcheckResult{Start: startedAt, ExitCode: 1, Output: "first\n"},
+						&container.HealthcheckResult{Start: startedAt.Add(time.Second), ExitCode: 1, Output: "second\n"},
+						&container.HealthcheckResult{Start: startedAt.Add(2 * time.Second), ExitCode: 2},
+					)), nil)
+			}),
+			expectedError: "health check failed: container is unhealthy\n" +
+				"last health checks:\n" +
+				"- 2020-01-02T03:04:06Z (exit code 1): second\n" +
+				"- 2020-01-02T03:04:07Z (exit code 2)",
+		},
+		{
+			scenario: "starting then healthy",
+			mockTarget: waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(healthCheck, &container.State{Status: "created"}), nil).Once()
+
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(healthCheck, runningHealthState(container.Starting)), nil).Once()
+
+				t.On("Inspect", isContext).
+					Return(nativeInspectResponse(healthCheck, runningHealthState(container.Healthy)), nil).Once()
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			s := wait.ForNativeHealthCheck().
+				WithPollInterval(time.Millisecond).
+				WithLogEntries(2)
+
+			err := s.WaitUntilReady(context.Background(), tc.mockTarget(t))
+
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestForNativeHealthCheck_Unhealthy(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse([]string{"CMD", "true"}, runningHealthState(container.Unhealthy)), nil)
+	})(t)
+
+	err := wait.ForNativeHealthCheck().WaitUntilReady(context.Background(), target)
+
+	var unhealthyErr *wait.UnhealthyError
+
+	require.ErrorAs(t, err, &unhealthyErr)
+	assert.ErrorIs(t, err, wait.ErrUnhealthy)
+	assert.Empty(t, unhealthyErr.Logs)
+	assert.EqualError(t, err, "health check failed: container is unhealthy")
+}
+
+func TestForNativeHealthCheck_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse([]string{"CMD", "true"}, runningHealthState(container.Starting)), nil)
+	})(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := wait.ForNativeHealthCheck().
+		WithPollInterval(5*time.Millisecond).
+		WaitUntilReady(ctx, target)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHealthCheckStrategy_WithNativeHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse([]string{"CMD", "pg_isready"}, runningHealthState(container.Starting)), nil).Once()
+
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse([]string{"CMD", "pg_isready"}, runningHealthState(container.Healthy)), nil).Once()
+	})(t)
+
+	err := wait.ForHealthCheckCmd("pg_isready").
+		WithNativeHealthCheck().
+		WaitUntilReady(context.Background(), target)
+
+	assert.NoError(t, err)
+}
+
+func TestHealthCheckStrategy_WithNativeHealthCheck_TestFunc(t *testing.T) {
+	t.Parallel()
+
+	called := 0
+
+	err := wait.ForHealthCheck(func(context.Context, wait.StrategyTarget) (bool, error) {
+		called++
+
+		return true, nil
+	}).
+		WithNativeHealthCheck().
+		WaitUntilReady(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, called, "the test func should run in the test process")
+}
+
+func TestHealthCheckStrategy_WithNativeHealthCheck_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse([]string{"CMD-SHELL", "curl -f localhost"}, runningHealthState(container.Healthy)), nil).Once()
+	})(t)
+
+	err := wait.ForHealthCheckCmd("pg_isready").
+		WithNativeHealthCheck().
+		WaitUntilReady(context.Background(), target)
+
+	expected := `health check failed: health check of the container does not match: expected ["CMD" "pg_isready"], got ["CMD-SHELL" "curl -f localhost"]`
+
+	require.ErrorIs(t, err, wait.ErrHealthCheckMismatch)
+	assert.EqualError(t, err, expected)
+}
+
+func TestHealthCheckStrategy_WithNativeHealthCheck_NoHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	target := waitmock.MockStrategyTarget(func(t *waitmock.StrategyTarget) {
+		t.On("Inspect", isContext).
+			Return(nativeInspectResponse(nil, runningHealthState(container.Healthy)), nil).Once()
+	})(t)
+
+	err := wait.ForHealthCheckCmd("pg_isready").
+		WithNativeHealthCheck().
+		WaitUntilReady(context.Background(), target)
+
+	assert.ErrorIs(t, err, wait.ErrNoHealthCheck)
+}
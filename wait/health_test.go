@@ -196,10 +196,34 @@ func TestHealthCheckStrategy_WithTestInterval_MaxRetriesExceeded(t *testing.T) {
 		WithRetries(1)
 
 	err := s.WaitUntilReady(context.Background(), nil)
-	expected := "health check failed: max retries exceeded"
 
-	assert.ErrorIs(t, err, wait.ErrMaxRetriesExceeded)
-	assert.EqualError(t, err, expected)
+	assertHealthCheckError(t, err, -1, -1)
+}
+
+// nolint: paralleltest
+func TestHealthCheckStrategy_WithStartInterval(t *testing.T) {
+	// The start interval (1ms) is used during the start period (20ms), then the test interval (1h) until the context is
+	// canceled. Therefore the test is called a few times in the start period, then once after that.
+	called := 0
+
+	s := wait.ForHealthCheck(func(context.Context, wait.StrategyTarget) (success bool, err error) {
+		called++
+
+		return false, nil
+	}).
+		WithTestTimeout(10 * time.Millisecond).
+		WithTestInterval(time.Hour).
+		WithStartInterval(time.Millisecond).
+		WithStartPeriod(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := s.WaitUntilReady(ctx, nil)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, called, 3, "test should be called with the start interval during the start period")
+	assert.LessOrEqual(t, called, 22, "test should be called with the test interval after the start period")
 }
 
 // nolint: unparam